@@ -0,0 +1,66 @@
+package loginsrv_grpc
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Authorizer decides whether the owner of a valid token may access a resource,
+// the full grpc method for RPCs and the request path for http requests
+// a plain error is reported as PermissionDenied
+type Authorizer func(ctx context.Context, profile *Profile, resource string) error
+
+// WithAuthorizer applies the authorizer after the token validation
+// of Authenticate and of the other front doors of the server
+func WithAuthorizer(authorizer Authorizer) Option {
+	return func(s *LoginSrvServer) {
+		s.authorizer = authorizer
+	}
+}
+
+// RequireGroups authorizes the members of any of the groups
+func RequireGroups(groups ...string) Authorizer {
+	return func(ctx context.Context, profile *Profile, resource string) error {
+		for _, group := range profile.Groups {
+			for _, required := range groups {
+				if group == required {
+					return nil
+				}
+			}
+		}
+		return status.Errorf(codes.PermissionDenied, "access to %s requires one of the groups %v", resource, groups)
+	}
+}
+
+// authorize validates the token, or the token behind a reference handle,
+// then applies the authorizer to the resource
+func (s *LoginSrvServer) authorize(ctx context.Context, token string, resource string) (*Profile, error) {
+	token, err := s.resolveToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := s.validateToken(token)
+	if err != nil {
+		return nil, err
+	}
+	if s.authorizer == nil {
+		return profile, nil
+	}
+
+	if err := s.authorizer(ctx, profile, resource); err != nil {
+		if _, ok := status.FromError(err); !ok {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, err
+	}
+	return profile, nil
+}
+
+// methodOf returns the full grpc method of the RPC context
+func methodOf(ctx context.Context) string {
+	method, _ := grpc.Method(ctx)
+	return method
+}
@@ -0,0 +1,63 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// methodStreamStub names the RPC method of a context
+type methodStreamStub struct {
+	grpc.ServerTransportStream
+	method string
+}
+
+func (s *methodStreamStub) Method() string {
+	return s.method
+}
+
+func rpcContext(token string, method string) context.Context {
+	ctx := md.NewIncomingContext(context.Background(), md.Pairs(AuthTokenMetadataKey, "bearer "+token))
+	return grpc.NewContextWithServerTransportStream(ctx, &methodStreamStub{method: method})
+}
+
+func TestAuthenticateAppliesAuthorizer(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+
+	var resources []string
+	srv := NewLoginSrvServer(upstream.URL, WithAuthorizer(func(ctx context.Context, profile *Profile, resource string) error {
+		resources = append(resources, resource)
+		if resource == "/billing.Billing/Refund" {
+			return errors.New("refunds are closed")
+		}
+		return RequireGroups("dev")(ctx, profile, resource)
+	}))
+	token := obtainTokenOrFail(t, srv)
+
+	ctx, err := srv.Authenticate(rpcContext(token, "/orders.Orders/List"))
+	if err != nil {
+		t.Fatal("Authorized method should pass", err)
+	}
+	if profile, ok := ProfileFromContext(ctx); !ok || profile.Sub != "bob" {
+		t.Error("Profile should be available after authorization")
+	}
+
+	_, err = srv.Authenticate(rpcContext(token, "/billing.Billing/Refund"))
+	if status.Code(err) != codes.PermissionDenied || status.Convert(err).Message() != "refunds are closed" {
+		t.Errorf("Plain authorizer errors should deny the method, got %v", err)
+	}
+	if len(resources) != 2 || resources[0] != "/orders.Orders/List" {
+		t.Errorf("Authorizer should receive the full method, got %v", resources)
+	}
+
+	srv = NewLoginSrvServer(upstream.URL, WithAuthorizer(RequireGroups("admin")))
+	if _, err := srv.Authenticate(rpcContext(token, "/orders.Orders/List")); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("Members outside of the required groups should be denied, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package loginsrv_grpc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrMalformedToken is returned when a token is not a jwt
+var ErrMalformedToken = errors.New("malformed token")
+
+// Claims are the claims loginsrv puts in its tokens
+// and returns as the user info of a session
+type Claims struct {
+	Sub       string   `json:"sub"`
+	Picture   string   `json:"picture,omitempty"`
+	Name      string   `json:"name,omitempty"`
+	Email     string   `json:"email,omitempty"`
+	Origin    string   `json:"origin,omitempty"`
+	Expiry    int64    `json:"exp,omitempty"`
+	Refreshes int      `json:"refs,omitempty"`
+	Domain    string   `json:"domain,omitempty"`
+	Groups    []string `json:"groups,omitempty"`
+
+	// Extra holds the custom claims added by the loginsrv user file
+	Extra map[string]interface{} `json:"-"`
+}
+
+var knownClaims = []string{"sub", "picture", "name", "email", "origin", "exp", "refs", "domain", "groups"}
+
+// ParseUnverifiedClaims decodes the claims of a loginsrv token
+// UNVERIFIED: the signature is not checked, so the claims are only
+// informative for the client and must never be used for authorization
+func ParseUnverifiedClaims(token string) (*Claims, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(segs[1])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	return parseClaims(payload)
+}
+
+// UnverifiedClaims decodes the access token of the reply
+// see ParseUnverifiedClaims
+func (m *LoginReply) UnverifiedClaims() (*Claims, error) {
+	return ParseUnverifiedClaims(m.GetAccessToken())
+}
+
+// ExpiresAt returns the expiry of the token or the zero time when it has none
+func (c *Claims) ExpiresAt() time.Time {
+	if c.Expiry == 0 {
+		return time.Time{}
+	}
+	return time.Unix(c.Expiry, 0)
+}
+
+// Profile converts the claims to the Profile message
+func (c *Claims) Profile() *Profile {
+	return &Profile{
+		Sub:       c.Sub,
+		Picture:   c.Picture,
+		Name:      c.Name,
+		Email:     c.Email,
+		Origin:    c.Origin,
+		Expiry:    c.Expiry,
+		Refreshes: int32(c.Refreshes),
+		Domain:    c.Domain,
+		Groups:    c.Groups,
+	}
+}
+
+func parseClaims(data []byte) (*Claims, error) {
+	claims := &Claims{}
+	if err := json.Unmarshal(data, claims); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	extra := map[string]interface{}{}
+	if err := json.Unmarshal(data, &extra); err != nil {
+		return nil, ErrMalformedToken
+	}
+	for _, name := range knownClaims {
+		delete(extra, name)
+	}
+	if len(extra) > 0 {
+		claims.Extra = extra
+	}
+	return claims, nil
+}
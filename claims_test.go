@@ -0,0 +1,50 @@
+package loginsrv_grpc
+
+import (
+	"testing"
+)
+
+func TestParseUnverifiedClaims(t *testing.T) {
+	reply := &LoginReply{AccessToken: unsignedTestToken(map[string]interface{}{
+		"sub":     "bob",
+		"picture": "https://example.com/bob.png",
+		"name":    "Bob",
+		"email":   "bob@example.com",
+		"origin":  "simple",
+		"exp":     1577836800,
+		"refs":    2,
+		"domain":  "example.com",
+		"groups":  []string{"admin", "dev"},
+		"tenant":  "acme",
+	})}
+
+	claims, err := reply.UnverifiedClaims()
+	if err != nil {
+		t.Fatal("Claims could not be parsed", err)
+	}
+
+	if claims.Sub != "bob" || claims.Picture == "" || claims.Name != "Bob" ||
+		claims.Email != "bob@example.com" || claims.Origin != "simple" || claims.Domain != "example.com" {
+		t.Errorf("Unexpected claims %+v", claims)
+	}
+	if claims.ExpiresAt().Unix() != 1577836800 || claims.Refreshes != 2 {
+		t.Errorf("Unexpected expiry or refreshes %+v", claims)
+	}
+	if len(claims.Groups) != 2 || claims.Groups[1] != "dev" {
+		t.Errorf("Unexpected groups %v", claims.Groups)
+	}
+	if len(claims.Extra) != 1 || claims.Extra["tenant"] != "acme" {
+		t.Errorf("Unexpected extra claims %v", claims.Extra)
+	}
+	if claims.Profile().Refreshes != 2 {
+		t.Error("Profile should carry the refreshes")
+	}
+}
+
+func TestParseUnverifiedClaimsRejectsMalformedToken(t *testing.T) {
+	for _, token := range []string{"", "abc", "a.%%%.c", "a.bm90LWpzb24.c"} {
+		if _, err := ParseUnverifiedClaims(token); err != ErrMalformedToken {
+			t.Errorf("Token %q should be malformed but got %v", token, err)
+		}
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -13,7 +15,19 @@ const (
 )
 
 // NewClientTokenInterceptor attaches a token to the outgoing RPC
+// a token already attached to the call context is left untouched
 func NewClientTokenInterceptor(tokenGetter TokenGetter) grpc.UnaryClientInterceptor {
+	return NewClientContextTokenInterceptor(AdaptTokenGetter(tokenGetter))
+}
+
+// NewClientStreamTokenInterceptor attaches a token to the outgoing streaming RPC
+func NewClientStreamTokenInterceptor(tokenGetter TokenGetter) grpc.StreamClientInterceptor {
+	return NewClientContextStreamTokenInterceptor(AdaptTokenGetter(tokenGetter))
+}
+
+// NewClientContextTokenInterceptor attaches the token returned for the RPC
+// getter errors fail the RPC with Unauthenticated before it is sent
+func NewClientContextTokenInterceptor(tokenGetter ContextTokenGetter) grpc.UnaryClientInterceptor {
 	return func(
 		ctx context.Context,
 		method string,
@@ -23,12 +37,69 @@ func NewClientTokenInterceptor(tokenGetter TokenGetter) grpc.UnaryClientIntercep
 		invoker grpc.UnaryInvoker,
 		opts ...grpc.CallOption) error {
 
-		if token := tokenGetter(); token != nil && len(*token) > 0 {
-			ctx = md.AppendToOutgoingContext(ctx, AuthTokenMetadataKey, "bearer "+*token)
+		ctx, err := withTokenFrom(ctx, method, tokenGetter)
+		if err != nil {
+			return err
 		}
 		return invoker(ctx, method, req, reply, cc, opts...)
 	}
 }
 
+// NewClientContextStreamTokenInterceptor attaches the token returned for the streaming RPC
+func NewClientContextStreamTokenInterceptor(tokenGetter ContextTokenGetter) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+		ctx, err := withTokenFrom(ctx, method, tokenGetter)
+		if err != nil {
+			return nil, err
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
 // TokenGetter returns a jwt token
 type TokenGetter func() *string
+
+// ContextTokenGetter returns the jwt token to use for an RPC method
+// an empty token sends the RPC without authorization
+type ContextTokenGetter func(ctx context.Context, method string) (string, error)
+
+// AdaptTokenGetter lets a TokenGetter be used where a ContextTokenGetter is expected
+func AdaptTokenGetter(tokenGetter TokenGetter) ContextTokenGetter {
+	return func(ctx context.Context, method string) (string, error) {
+		if token := tokenGetter(); token != nil {
+			return *token, nil
+		}
+		return "", nil
+	}
+}
+
+func withTokenFrom(ctx context.Context, method string, tokenGetter ContextTokenGetter) (context.Context, error) {
+	if hasOutgoingToken(ctx) {
+		return ctx, nil
+	}
+
+	token, err := tokenGetter(ctx, method)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, status.Convert(err).Message())
+	}
+	if len(token) > 0 {
+		return withOutgoingToken(ctx, token), nil
+	}
+	return ctx, nil
+}
+
+func withOutgoingToken(ctx context.Context, token string) context.Context {
+	return md.AppendToOutgoingContext(ctx, AuthTokenMetadataKey, "bearer "+token)
+}
+
+func hasOutgoingToken(ctx context.Context) bool {
+	metadata, _ := md.FromOutgoingContext(ctx)
+	return len(metadata.Get(AuthTokenMetadataKey)) > 0
+}
@@ -0,0 +1,71 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestClientTokenInterceptorKeepsExplicitToken(t *testing.T) {
+	token := "from-getter"
+	interceptor := NewClientTokenInterceptor(func() *string { return &token })
+
+	var sent []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		metadata, _ := md.FromOutgoingContext(ctx)
+		sent = metadata.Get(AuthTokenMetadataKey)
+		return nil
+	}
+
+	ctx := withOutgoingToken(context.Background(), "explicit")
+	if err := interceptor(ctx, "/test", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent) != 1 || sent[0] != "bearer explicit" {
+		t.Errorf("Expected only the explicit token but got %v", sent)
+	}
+}
+
+func TestClientContextTokenInterceptorPicksTokenPerMethod(t *testing.T) {
+	interceptor := NewClientContextTokenInterceptor(func(ctx context.Context, method string) (string, error) {
+		return "token-for" + method, nil
+	})
+
+	var sent string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent = outgoingToken(ctx)
+		return nil
+	}
+
+	if err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if sent != "token-for/svc/Method" {
+		t.Error("Unexpected token " + sent)
+	}
+}
+
+func TestClientContextTokenInterceptorFailsOnGetterError(t *testing.T) {
+	interceptor := NewClientContextTokenInterceptor(func(ctx context.Context, method string) (string, error) {
+		return "", errors.New("no session")
+	})
+
+	invoked := false
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		invoked = true
+		return nil
+	}
+
+	err := interceptor(context.Background(), "/svc/Method", nil, nil, nil, invoker)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Error("Expected Unauthenticated but got", err)
+	}
+	if invoked {
+		t.Error("RPC should not be sent")
+	}
+}
@@ -5,12 +5,14 @@ import (
 	"net"
 
 	loginsrv_grpc "github.com/motia/loginsrv-grpc"
+	loginsrvv1 "github.com/motia/loginsrv-grpc/v1"
 
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
 
 	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 const (
@@ -43,6 +45,12 @@ func main() {
 		)),
 	)
 	loginsrv_grpc.RegisterAuthServer(s, server)
+	loginsrvv1.RegisterAuthServiceServer(s, loginsrvv1.NewAuthServiceServer(server))
+
+	healthChecker := loginsrv_grpc.NewHealthChecker(server)
+	healthChecker.Start()
+	defer healthChecker.Stop()
+	healthpb.RegisterHealthServer(s, healthChecker.Server())
 	log.Println("Auth sever started")
 	if err := s.Serve(lis); err != nil {
 		log.Fatalf("failed to serve: %v", err)
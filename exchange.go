@@ -0,0 +1,317 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JWTTokenType is the RFC 8693 type of the exchanged tokens
+const JWTTokenType = "urn:ietf:params:oauth:token-type:jwt"
+
+// ExchangePolicy decides whether the actor may exchange the subject token for the audience and scopes
+// a plain error is reported as PermissionDenied,
+// the subject claims of a token signed by the server keep its aud, act and scope in Extra,
+// tokens of loginsrv carry no scopes so the policy is all that bounds the scopes of a first exchange
+type ExchangePolicy func(ctx context.Context, actor *Profile, subject *Claims, audience string, scopes []string) error
+
+// WithSigningKey signs the tokens issued by the server, such as exchanged tokens,
+// RSA keys sign with RS256 and P-256 ECDSA keys with ES256, the key id names it in the token header
+func WithSigningKey(key crypto.Signer, keyID string) Option {
+	return func(s *LoginSrvServer) {
+		s.signingKey = &signingKey{key: key, keyID: keyID}
+	}
+}
+
+// WithIssuer sets the iss claim of the tokens signed by the server,
+// relying parties of the OIDCHandler expect its issuer url
+func WithIssuer(issuer string) Option {
+	return func(s *LoginSrvServer) {
+		s.issuer = strings.TrimSuffix(issuer, "/")
+	}
+}
+
+// WithExchangePolicy protects exchangeToken, without a policy the RPC is always rejected
+func WithExchangePolicy(policy ExchangePolicy) Option {
+	return func(s *LoginSrvServer) {
+		s.exchangePolicy = policy
+	}
+}
+
+// WithExchangeLifetime sets the lifetime of exchanged tokens, the default is 5 minutes
+// they never outlive the subject token
+func WithExchangeLifetime(d time.Duration) Option {
+	return func(s *LoginSrvServer) {
+		s.exchangeLifetime = d
+	}
+}
+
+// AudiencesByGroup allows the actors of a group to exchange tokens for the audiences of the group,
+// the exchanged tokens may only carry the allowed scopes
+// and a subject token with an aud, like an exchanged one, is only exchanged by the actor it names
+func AudiencesByGroup(audiences map[string][]string, allowedScopes ...string) ExchangePolicy {
+	return func(ctx context.Context, actor *Profile, subject *Claims, audience string, scopes []string) error {
+		if _, ok := subject.Extra["aud"]; ok {
+			named := false
+			for _, aud := range claimAudiences(subject) {
+				named = named || aud == actor.Sub
+			}
+			if !named {
+				return status.Errorf(codes.PermissionDenied, "subject token is not meant for %s", actor.Sub)
+			}
+		}
+		for _, scope := range scopes {
+			allowed := false
+			for _, a := range allowedScopes {
+				allowed = allowed || a == scope
+			}
+			if !allowed {
+				return status.Errorf(codes.PermissionDenied, "scope %q may not be exchanged", scope)
+			}
+		}
+		for _, group := range actor.Groups {
+			for _, allowed := range audiences[group] {
+				if allowed == audience {
+					return nil
+				}
+			}
+		}
+		return status.Errorf(codes.PermissionDenied, "%s may not exchange tokens for %s", actor.Sub, audience)
+	}
+}
+
+// ExchangeToken issues a short lived token of the subject for the audience, RFC 8693 style,
+// the caller authenticated through the context metadata is the actor recorded in the act claim,
+// actors of a previous exchange are nested in it
+func (s *LoginSrvServer) ExchangeToken(ctx context.Context, request *ExchangeTokenRequest) (*ExchangeTokenReply, error) {
+	if s.signingKey == nil {
+		return nil, grpc.Errorf(codes.FailedPrecondition, "token exchange is not configured")
+	}
+	if request.SubjectToken == "" || request.Audience == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "subject token and audience are required")
+	}
+
+	actorToken := s.tokenFromContext(ctx)
+	if actorToken == nil {
+		return nil, grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	actor, err := s.validateToken(*actorToken)
+	if err != nil {
+		return nil, err
+	}
+
+	subject, err := s.exchangeSubject(ctx, request.SubjectToken)
+	if err != nil {
+		return nil, err
+	}
+	scopes, err := downscope(subject, request.Scopes)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.exchangePolicy == nil {
+		return nil, grpc.Errorf(codes.PermissionDenied, "token exchange is disabled")
+	}
+	if err := s.exchangePolicy(ctx, actor, subject, request.Audience, scopes); err != nil {
+		if _, ok := status.FromError(err); !ok {
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	expiry := now.Add(s.exchangeLifetime)
+	if subject.Expiry != 0 && time.Unix(subject.Expiry, 0).Before(expiry) {
+		expiry = time.Unix(subject.Expiry, 0)
+	}
+
+	act := map[string]interface{}{"sub": actor.Sub}
+	if previous, ok := subject.Extra["act"]; ok {
+		act["act"] = previous
+	}
+	claims := map[string]interface{}{
+		"sub": subject.Sub,
+		"aud": request.Audience,
+		"iat": now.Unix(),
+		"exp": expiry.Unix(),
+		"act": act,
+	}
+	if s.issuer != "" {
+		claims["iss"] = s.issuer
+	}
+	if len(scopes) > 0 {
+		claims["scope"] = strings.Join(scopes, " ")
+	}
+
+	token, err := s.signingKey.sign(claims)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Internal, "Internal")
+	}
+	return &ExchangeTokenReply{
+		AccessToken:     token,
+		IssuedTokenType: JWTTokenType,
+		TokenType:       "bearer",
+		ExpiresIn:       int64(time.Until(expiry).Seconds()),
+		Scopes:          scopes,
+	}, nil
+}
+
+// exchangeSubject returns the claims of a token issued by loginsrv or by a previous exchange
+func (s *LoginSrvServer) exchangeSubject(ctx context.Context, token string) (*Claims, error) {
+	if claims, err := s.signingKey.verify(token); err == nil {
+		if claims.Expiry != 0 && !time.Now().Before(time.Unix(claims.Expiry, 0)) {
+			return nil, grpc.Errorf(codes.Unauthenticated, "subject token expired")
+		}
+		return claims, nil
+	}
+
+	token, err := s.resolveToken(ctx, token)
+	var profile *Profile
+	if err == nil {
+		profile, err = s.validateToken(token)
+	}
+	if err != nil {
+		return nil, grpc.Errorf(status.Code(err), "subject %s", status.Convert(err).Message())
+	}
+	return &Claims{
+		Sub:     profile.Sub,
+		Picture: profile.Picture,
+		Name:    profile.Name,
+		Email:   profile.Email,
+		Origin:  profile.Origin,
+		Expiry:  profile.Expiry,
+		Domain:  profile.Domain,
+		Groups:  profile.Groups,
+	}, nil
+}
+
+// downscope checks the requested scopes stay within the scopes of the subject,
+// a subject without scopes allows any, no request keeps the subject scopes
+func downscope(subject *Claims, requested []string) ([]string, error) {
+	scope, _ := subject.Extra["scope"].(string)
+	if scope == "" {
+		return requested, nil
+	}
+
+	granted := strings.Fields(scope)
+	if len(requested) == 0 {
+		return granted, nil
+	}
+	for _, r := range requested {
+		found := false
+		for _, g := range granted {
+			found = found || g == r
+		}
+		if !found {
+			return nil, grpc.Errorf(codes.PermissionDenied, "scope %q exceeds the subject token", r)
+		}
+	}
+	return requested, nil
+}
+
+// signingKey signs and verifies the tokens issued by the server
+type signingKey struct {
+	key   crypto.Signer
+	keyID string
+}
+
+func (k *signingKey) algorithm() string {
+	switch key := k.key.Public().(type) {
+	case *rsa.PublicKey:
+		return "RS256"
+	case *ecdsa.PublicKey:
+		if key.Curve == elliptic.P256() {
+			return "ES256"
+		}
+	}
+	return ""
+}
+
+func (k *signingKey) sign(claims map[string]interface{}) (string, error) {
+	alg := k.algorithm()
+	if alg == "" {
+		return "", ErrInvalidSignature
+	}
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT", "kid": k.keyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(content))
+
+	signature, err := k.key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", err
+	}
+	if alg == "ES256" {
+		// JWS wants the raw r and s values rather than the asn.1 signature of crypto.Signer
+		var rs struct{ R, S *big.Int }
+		if _, err := asn1.Unmarshal(signature, &rs); err != nil {
+			return "", err
+		}
+		signature = append(padBytes(rs.R.Bytes(), 32), padBytes(rs.S.Bytes(), 32)...)
+	}
+	return content + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// verify returns the claims of a token signed with the key
+func (k *signingKey) verify(token string) (*Claims, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 {
+		return nil, ErrMalformedToken
+	}
+	headerJSON, err := base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	header := struct {
+		Algorithm string `json:"alg"`
+		KeyID     string `json:"kid"`
+	}{}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(segs[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	if header.Algorithm != k.algorithm() || header.KeyID != k.keyID {
+		return nil, ErrInvalidSignature
+	}
+
+	digest := sha256.Sum256([]byte(segs[0] + "." + segs[1]))
+	switch key := k.key.Public().(type) {
+	case *rsa.PublicKey:
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) != nil {
+			return nil, ErrInvalidSignature
+		}
+	case *ecdsa.PublicKey:
+		if len(signature) != 64 {
+			return nil, ErrInvalidSignature
+		}
+		r, s := new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(key, digest[:], r, s) {
+			return nil, ErrInvalidSignature
+		}
+	}
+	return ParseUnverifiedClaims(token)
+}
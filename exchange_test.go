@@ -0,0 +1,171 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"reflect"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var exchangeTestSecret = []byte("exchange-secret")
+
+func exchangeTestToken(sub string, groups []string, lifetime time.Duration) string {
+	return signedTestToken(exchangeTestSecret, map[string]interface{}{
+		"sub":    sub,
+		"groups": groups,
+		"exp":    time.Now().Add(lifetime).Unix(),
+	})
+}
+
+func newExchangeTestServer(t *testing.T, options ...Option) *LoginSrvServer {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options = append([]Option{WithJWTSecret(exchangeTestSecret), WithSigningKey(key, "exchange-1")}, options...)
+	return NewLoginSrvServer("http://localhost:0", options...)
+}
+
+func TestExchangeTokenNestsActors(t *testing.T) {
+	srv := newExchangeTestServer(t, WithIssuer("https://auth.example.com/"), WithExchangePolicy(AudiencesByGroup(map[string][]string{
+		"services": {"billing", "ledger"},
+	}, "invoices:read", "invoices:write")))
+	bob := exchangeTestToken("bob", []string{"dev"}, time.Hour)
+	orders := &contextWithAuthorizationStub{authToken: exchangeTestToken("orders", []string{"services"}, time.Hour)}
+	billing := &contextWithAuthorizationStub{authToken: exchangeTestToken("billing", []string{"services"}, time.Hour)}
+
+	reply, err := srv.ExchangeToken(orders, &ExchangeTokenRequest{
+		SubjectToken: bob,
+		Audience:     "billing",
+		Scopes:       []string{"invoices:read"},
+	})
+	if err != nil {
+		t.Fatal("Exchange failed", err)
+	}
+	if reply.IssuedTokenType != JWTTokenType || reply.TokenType != "bearer" || reply.ExpiresIn <= 0 || reply.ExpiresIn > 300 {
+		t.Errorf("Unexpected reply %v", reply)
+	}
+	claims, err := srv.signingKey.verify(reply.AccessToken)
+	if err != nil {
+		t.Fatal("Exchanged token should be signed with the key", err)
+	}
+	if claims.Sub != "bob" || claims.Extra["aud"] != "billing" || claims.Extra["scope"] != "invoices:read" || claims.Extra["iss"] != "https://auth.example.com" {
+		t.Errorf("Unexpected claims %v %v", claims, claims.Extra)
+	}
+	if !reflect.DeepEqual(claims.Extra["act"], map[string]interface{}{"sub": "orders"}) {
+		t.Errorf("Caller should be the actor, got %v", claims.Extra["act"])
+	}
+
+	_, err = srv.ExchangeToken(billing, &ExchangeTokenRequest{
+		SubjectToken: reply.AccessToken,
+		Audience:     "ledger",
+		Scopes:       []string{"invoices:write"},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Error("Scopes should not widen through an exchange", err)
+	}
+
+	_, err = srv.ExchangeToken(orders, &ExchangeTokenRequest{SubjectToken: reply.AccessToken, Audience: "ledger"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Error("Only the audience of the subject token should exchange it", err)
+	}
+
+	chained, err := srv.ExchangeToken(billing, &ExchangeTokenRequest{SubjectToken: reply.AccessToken, Audience: "ledger"})
+	if err != nil {
+		t.Fatal("Chained exchange failed", err)
+	}
+	claims, _ = srv.signingKey.verify(chained.AccessToken)
+	expected := map[string]interface{}{"sub": "billing", "act": map[string]interface{}{"sub": "orders"}}
+	if !reflect.DeepEqual(claims.Extra["act"], expected) || claims.Extra["scope"] != "invoices:read" {
+		t.Errorf("Previous actors should be nested, got %v %v", claims.Extra["act"], claims.Extra["scope"])
+	}
+}
+
+func TestExchangeTokenPolicy(t *testing.T) {
+	bob := exchangeTestToken("bob", []string{"dev"}, time.Hour)
+	orders := &contextWithAuthorizationStub{authToken: exchangeTestToken("orders", []string{"services"}, time.Hour)}
+	request := &ExchangeTokenRequest{SubjectToken: bob, Audience: "billing"}
+
+	srv := NewLoginSrvServer("http://localhost:0", WithJWTSecret(exchangeTestSecret))
+	if _, err := srv.ExchangeToken(orders, request); status.Code(err) != codes.FailedPrecondition {
+		t.Error("Exchange should need a signing key", err)
+	}
+
+	srv = newExchangeTestServer(t)
+	if _, err := srv.ExchangeToken(orders, request); status.Code(err) != codes.PermissionDenied {
+		t.Error("Exchange should be disabled without policy", err)
+	}
+
+	srv = newExchangeTestServer(t, WithExchangePolicy(AudiencesByGroup(map[string][]string{"services": {"ledger"}})))
+	if _, err := srv.ExchangeToken(orders, request); status.Code(err) != codes.PermissionDenied {
+		t.Error("Audience outside of the policy should be denied", err)
+	}
+	scoped := &ExchangeTokenRequest{SubjectToken: bob, Audience: "ledger", Scopes: []string{"admin"}}
+	if _, err := srv.ExchangeToken(orders, scoped); status.Code(err) != codes.PermissionDenied {
+		t.Error("Scopes outside of the policy should be denied", err)
+	}
+	if _, err := srv.ExchangeToken(&contextWithAuthorizationStub{}, request); status.Code(err) != codes.Unauthenticated {
+		t.Error("Anonymous callers should be rejected", err)
+	}
+	if _, err := srv.ExchangeToken(orders, &ExchangeTokenRequest{SubjectToken: "garbage", Audience: "ledger"}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Invalid subject token should be rejected", err)
+	}
+
+	srv = newExchangeTestServer(t, WithExchangePolicy(func(ctx context.Context, actor *Profile, subject *Claims, audience string, scopes []string) error {
+		return nil
+	}))
+	expiring := exchangeTestToken("bob", nil, 30*time.Second)
+	reply, err := srv.ExchangeToken(orders, &ExchangeTokenRequest{SubjectToken: expiring, Audience: "billing"})
+	if err != nil || reply.ExpiresIn > 30 {
+		t.Error("Exchanged token should not outlive the subject token", reply, err)
+	}
+}
+
+// opaqueSigner hides the concrete key, like the signers of a KMS or an HSM
+type opaqueSigner struct {
+	crypto.Signer
+}
+
+func TestSigningKeyECDSASigner(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := (&signingKey{key: opaqueSigner{ecdsaKey}, keyID: "kms-1"}).sign(map[string]interface{}{"sub": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, err := (&signingKey{key: ecdsaKey, keyID: "kms-1"}).verify(token); err != nil || claims.Sub != "bob" {
+		t.Error("ES256 token of an opaque signer should verify", claims, err)
+	}
+}
+
+func TestSigningKeyRSA(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := &signingKey{key: rsaKey, keyID: "rsa-1"}
+
+	token, err := key.sign(map[string]interface{}{"sub": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims, err := key.verify(token); err != nil || claims.Sub != "bob" {
+		t.Error("RS256 token should verify", claims, err)
+	}
+	if _, err := key.verify(token[:len(token)-4] + "AAAA"); err != ErrInvalidSignature {
+		t.Error("Tampered token should not verify", err)
+	}
+	if _, err := (&signingKey{key: rsaKey, keyID: "rsa-2"}).verify(token); err != ErrInvalidSignature {
+		t.Error("Token of another key id should not verify", err)
+	}
+}
@@ -0,0 +1,96 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"strings"
+
+	corev3 "github.com/envoyproxy/go-control-plane/envoy/config/core/v3"
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"github.com/golang/protobuf/ptypes/wrappers"
+	rpcstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// SubHeader carries the subject of an authorized request to the upstream
+	SubHeader = "x-auth-sub"
+	// GroupsHeader carries the comma separated groups of an authorized request to the upstream
+	GroupsHeader = "x-auth-groups"
+)
+
+// ExtAuthzServer implements the envoy.service.auth.v3.Authorization service,
+// requests are authorized like the RPCs of Authenticate with their path as resource
+type ExtAuthzServer struct {
+	srv *LoginSrvServer
+}
+
+// NewExtAuthzServer creates the ext_authz server to register with authv3.RegisterAuthorizationServer
+func NewExtAuthzServer(srv *LoginSrvServer) *ExtAuthzServer {
+	return &ExtAuthzServer{srv: srv}
+}
+
+// AuthFuncOverride skips Authenticate for envoy, the token to check is part of the request
+func (a *ExtAuthzServer) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	return ctx, nil
+}
+
+// Check authorizes the http request described by envoy
+// identity headers replace any value sent by the client
+func (a *ExtAuthzServer) Check(ctx context.Context, request *authv3.CheckRequest) (*authv3.CheckResponse, error) {
+	httpRequest := request.GetAttributes().GetRequest().GetHttp()
+	token := bearerTokenOf(httpRequest.GetHeaders()["authorization"])
+	if token == "" {
+		return deniedCheckResponse(status.New(codes.Unauthenticated, "missing token")), nil
+	}
+
+	path := strings.SplitN(httpRequest.GetPath(), "?", 2)[0]
+	profile, err := a.srv.authorize(ctx, token, path)
+	if err != nil {
+		return deniedCheckResponse(status.Convert(err)), nil
+	}
+
+	return &authv3.CheckResponse{
+		Status: &rpcstatus.Status{Code: int32(codes.OK)},
+		HttpResponse: &authv3.CheckResponse_OkResponse{
+			OkResponse: &authv3.OkHttpResponse{
+				Headers: []*corev3.HeaderValueOption{
+					replaceHeader(SubHeader, profile.Sub),
+					replaceHeader(GroupsHeader, strings.Join(profile.Groups, ",")),
+				},
+			},
+		},
+	}, nil
+}
+
+func deniedCheckResponse(s *status.Status) *authv3.CheckResponse {
+	denied := &authv3.DeniedHttpResponse{
+		Status: &typev3.HttpStatus{Code: typev3.StatusCode(httpStatusFromCode(s.Code()))},
+		Body:   s.Message(),
+	}
+	if s.Code() == codes.Unauthenticated {
+		denied.Headers = append(denied.Headers, replaceHeader("www-authenticate", "Bearer"))
+	}
+
+	return &authv3.CheckResponse{
+		Status:       &rpcstatus.Status{Code: int32(s.Code()), Message: s.Message()},
+		HttpResponse: &authv3.CheckResponse_DeniedResponse{DeniedResponse: denied},
+	}
+}
+
+func replaceHeader(key string, value string) *corev3.HeaderValueOption {
+	return &corev3.HeaderValueOption{
+		Header: &corev3.HeaderValue{Key: key, Value: value},
+		Append: &wrappers.BoolValue{Value: false},
+	}
+}
+
+// bearerTokenOf returns the token of an authorization header value, if any
+func bearerTokenOf(authorization string) string {
+	segs := strings.SplitN(authorization, " ", 2)
+	if len(segs) < 2 || !strings.EqualFold(segs[0], "bearer") {
+		return ""
+	}
+	return segs[1]
+}
@@ -0,0 +1,90 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"testing"
+
+	authv3 "github.com/envoyproxy/go-control-plane/envoy/service/auth/v3"
+	typev3 "github.com/envoyproxy/go-control-plane/envoy/type/v3"
+	"google.golang.org/grpc/codes"
+)
+
+func checkRequest(path string, headers map[string]string) *authv3.CheckRequest {
+	return &authv3.CheckRequest{
+		Attributes: &authv3.AttributeContext{
+			Request: &authv3.AttributeContext_Request{
+				Http: &authv3.AttributeContext_HttpRequest{
+					Method:  "GET",
+					Path:    path,
+					Headers: headers,
+				},
+			},
+		},
+	}
+}
+
+func TestExtAuthzAllows(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	token := obtainTokenOrFail(t, srv)
+
+	response, err := NewExtAuthzServer(srv).Check(context.Background(), checkRequest("/orders?page=2", map[string]string{
+		"authorization": "Bearer " + token,
+		SubHeader:       "mallory",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if codes.Code(response.Status.Code) != codes.OK || response.GetOkResponse() == nil {
+		t.Fatalf("Request should be allowed but got %v", response)
+	}
+
+	headers := map[string]string{}
+	for _, option := range response.GetOkResponse().Headers {
+		if option.Append.GetValue() {
+			t.Errorf("Identity header %s should replace the client value", option.Header.Key)
+		}
+		headers[option.Header.Key] = option.Header.Value
+	}
+	if headers[SubHeader] != "bob" || headers[GroupsHeader] != "dev" {
+		t.Errorf("Unexpected identity headers %v", headers)
+	}
+}
+
+func TestExtAuthzDenies(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	var resource string
+	srv := NewLoginSrvServer(upstream.URL, WithAuthorizer(func(ctx context.Context, profile *Profile, r string) error {
+		resource = r
+		return RequireGroups("admin")(ctx, profile, r)
+	}))
+	token := obtainTokenOrFail(t, srv)
+	authz := NewExtAuthzServer(srv)
+
+	for _, tc := range []struct {
+		headers map[string]string
+		code    codes.Code
+		status  typev3.StatusCode
+	}{
+		{map[string]string{}, codes.Unauthenticated, typev3.StatusCode_Unauthorized},
+		{map[string]string{"authorization": "Bearer garbage"}, codes.Unauthenticated, typev3.StatusCode_Unauthorized},
+		{map[string]string{"authorization": "Bearer " + token}, codes.PermissionDenied, typev3.StatusCode_Forbidden},
+	} {
+		response, err := authz.Check(context.Background(), checkRequest("/admin/users?id=1", tc.headers))
+		if err != nil {
+			t.Fatal(err)
+		}
+		denied := response.GetDeniedResponse()
+		if codes.Code(response.Status.Code) != tc.code || denied == nil || denied.Status.Code != tc.status || denied.Body == "" {
+			t.Errorf("Expected a %v denial but got %v", tc.code, response)
+		}
+		if tc.code == codes.Unauthenticated && (len(denied.Headers) != 1 || denied.Headers[0].Header.Key != "www-authenticate") {
+			t.Errorf("Unauthenticated denial should challenge the client, got %v", denied.Headers)
+		}
+	}
+	if resource != "/admin/users" {
+		t.Errorf("Authorizer should receive the request path, got %q", resource)
+	}
+}
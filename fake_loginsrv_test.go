@@ -0,0 +1,195 @@
+package loginsrv_grpc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// fakeLoginsrv mimics the loginsrv endpoints used by LoginSrvServer
+// with the simple backend user bob:secret
+type fakeLoginsrv struct {
+	*httptest.Server
+	provider *httptest.Server
+
+	mu       sync.Mutex
+	issued   map[string]*Claims
+	serial   int64
+	logouts  int
+	lookups  int
+	lifetime time.Duration
+	lastPath string
+	lastForm url.Values
+	// oauthFailures is the number of oauth callbacks failing before loginsrv recovers
+	oauthFailures int
+}
+
+func newFakeLoginsrv() *fakeLoginsrv {
+	f := &fakeLoginsrv{issued: map[string]*Claims{}, lifetime: time.Hour}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", f.handleLogin)
+	mux.HandleFunc("/login/", f.handleLogin)
+	mux.HandleFunc("/login/github", f.handleOAuth)
+	f.Server = httptest.NewServer(mux)
+	f.provider = newFakeOAuthProvider()
+	return f
+}
+
+func (f *fakeLoginsrv) Close() {
+	f.provider.Close()
+	f.Server.Close()
+}
+
+func (f *fakeLoginsrv) handleLogin(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/login" && r.Method != "POST" {
+		// not a configured oauth provider
+		http.NotFound(w, r)
+		return
+	}
+	if r.FormValue("logout") == "true" {
+		f.mu.Lock()
+		f.logouts++
+		f.mu.Unlock()
+		http.SetCookie(w, &http.Cookie{Name: jwtCookieName, Value: "delete", Path: "/", MaxAge: -1})
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	claims := f.claimsOf(r)
+	f.mu.Lock()
+	if r.Method == "GET" {
+		f.lookups++
+	} else {
+		r.ParseForm()
+		f.lastPath, f.lastForm = r.URL.Path, r.PostForm
+	}
+	f.mu.Unlock()
+	switch {
+	case r.Method == "GET" && claims != nil:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(claims)
+	case r.Method == "POST" && claims != nil:
+		refreshed := *claims
+		refreshed.Refreshes++
+		fmt.Fprint(w, f.issue(&refreshed))
+	case r.Method == "POST" && r.PostFormValue("username") == "bob" && r.PostFormValue("password") == "secret":
+		fmt.Fprint(w, f.issue(&Claims{Sub: "bob", Origin: "simple", Groups: []string{"dev"}}))
+	default:
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "Wrong credentials")
+	}
+}
+
+func (f *fakeLoginsrv) lookupCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.lookups
+}
+
+func (f *fakeLoginsrv) resetLookups() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.lookups = 0
+}
+
+func (f *fakeLoginsrv) claimsOf(r *http.Request) *Claims {
+	cookie, err := r.Cookie(jwtCookieName)
+	if err != nil {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.issued[cookie.Value]
+}
+
+func (f *fakeLoginsrv) issue(claims *Claims) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.serial++
+	claims.Expiry = time.Now().Add(f.lifetime).Unix()
+	token := unsignedTestToken(map[string]interface{}{
+		"sub":    claims.Sub,
+		"origin": claims.Origin,
+		"groups": claims.Groups,
+		"exp":    claims.Expiry,
+		"refs":   claims.Refreshes,
+		"serial": f.serial,
+	})
+	f.issued[token] = claims
+	return token
+}
+
+// handleOAuth runs the loginsrv oauth flow against the fake provider
+func (f *fakeLoginsrv) handleOAuth(w http.ResponseWriter, r *http.Request) {
+	code := r.FormValue("code")
+	if code == "" {
+		f.mu.Lock()
+		f.serial++
+		state := fmt.Sprintf("state-%d", f.serial)
+		f.mu.Unlock()
+
+		http.SetCookie(w, &http.Cookie{Name: oauthStateCookieName, Value: state, HttpOnly: true})
+		query := url.Values{}
+		query.Set("client_id", "client")
+		query.Set("redirect_uri", f.URL+"/login/github")
+		query.Set("state", state)
+		http.Redirect(w, r, f.provider.URL+"/authorize?"+query.Encode(), http.StatusSeeOther)
+		return
+	}
+
+	f.mu.Lock()
+	failing := f.oauthFailures > 0
+	if failing {
+		f.oauthFailures--
+	}
+	f.mu.Unlock()
+	if failing {
+		w.WriteHeader(http.StatusBadGateway)
+		return
+	}
+
+	stateCookie, err := r.Cookie(oauthStateCookieName)
+	if err != nil || stateCookie.Value != r.FormValue("state") {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, "oauth state param could not be verified")
+		return
+	}
+
+	resp, err := http.PostForm(f.provider.URL+"/token", url.Values{"code": {code}})
+	if err != nil || resp.StatusCode != http.StatusOK {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	defer resp.Body.Close()
+	user := struct {
+		Login string `json:"login"`
+	}{}
+	json.NewDecoder(resp.Body).Decode(&user)
+
+	fmt.Fprint(w, f.issue(&Claims{Sub: user.Login, Origin: "github"}))
+}
+
+// newFakeOAuthProvider authorizes every user agent as octocat
+func newFakeOAuthProvider() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := url.Values{}
+		query.Set("code", "octocat-code")
+		query.Set("state", r.FormValue("state"))
+		http.Redirect(w, r, r.FormValue("redirect_uri")+"?"+query.Encode(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("code") != "octocat-code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"login":"octocat"}`)
+	})
+	return httptest.NewServer(mux)
+}
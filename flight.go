@@ -0,0 +1,43 @@
+package loginsrv_grpc
+
+import (
+	"sync"
+)
+
+// flightGroup shares the result of a profile lookup between the concurrent callers for the same token
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+type flightCall struct {
+	wg      sync.WaitGroup
+	profile *Profile
+	err     error
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: map[string]*flightCall{}}
+}
+
+func (g *flightGroup) do(key string, fn func() (*Profile, error)) (*Profile, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[key]; ok {
+		g.mu.Unlock()
+		call.wg.Wait()
+		return call.profile, call.err
+	}
+
+	call := &flightCall{}
+	call.wg.Add(1)
+	g.calls[key] = call
+	g.mu.Unlock()
+
+	call.profile, call.err = fn()
+	call.wg.Done()
+
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+	return call.profile, call.err
+}
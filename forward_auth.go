@@ -0,0 +1,53 @@
+package loginsrv_grpc
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ForwardAuthHandler answers the verify subrequests of nginx auth_request and traefik ForwardAuth,
+// 200 with the x-auth-sub and x-auth-groups headers, 401 or 403 otherwise
+// the token and the authorizer resource are read like in Middleware,
+// the resource being the original uri sent by the proxy
+type ForwardAuthHandler struct {
+	srv *LoginSrvServer
+}
+
+// NewForwardAuthHandler creates the verify endpoint to point the proxy at
+func NewForwardAuthHandler(srv *LoginSrvServer) *ForwardAuthHandler {
+	return &ForwardAuthHandler{srv: srv}
+}
+
+func (h *ForwardAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := requestToken(r, h.srv.httpCookieName())
+	if token == "" {
+		writeHTTPError(w, status.Error(codes.Unauthenticated, "missing token"))
+		return
+	}
+
+	profile, err := h.srv.authorize(r.Context(), token, originalPath(r))
+	if err != nil {
+		writeHTTPError(w, err)
+		return
+	}
+
+	w.Header().Set(SubHeader, profile.Sub)
+	w.Header().Set(GroupsHeader, strings.Join(profile.Groups, ","))
+	w.WriteHeader(http.StatusOK)
+}
+
+// originalPath returns the path of the request verified for the proxy,
+// traefik sends X-Forwarded-Uri and nginx is usually configured with X-Original-URI
+func originalPath(r *http.Request) string {
+	uri := r.Header.Get("X-Forwarded-Uri")
+	if uri == "" {
+		uri = r.Header.Get("X-Original-URI")
+	}
+	if uri == "" {
+		return r.URL.Path
+	}
+	return strings.SplitN(uri, "?", 2)[0]
+}
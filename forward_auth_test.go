@@ -0,0 +1,62 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestForwardAuth(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	var resource string
+	srv := NewLoginSrvServer(upstream.URL, WithValidationCache(time.Minute), WithAuthorizer(func(ctx context.Context, profile *Profile, r string) error {
+		resource = r
+		if r == "/admin" {
+			return RequireGroups("admin")(ctx, profile, r)
+		}
+		return nil
+	}))
+	token := obtainTokenOrFail(t, srv)
+	h := NewForwardAuthHandler(srv)
+
+	verify := func(header string, value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/verify", nil)
+		req.Header.Set(header, value)
+		req.Header.Set("X-Forwarded-Uri", "/orders?page=2")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	upstream.resetLookups()
+	for _, w := range []*httptest.ResponseRecorder{
+		verify("Authorization", "Bearer "+token),
+		verify("Cookie", jwtCookieName+"="+token),
+	} {
+		if w.Code != http.StatusOK || w.Header().Get(SubHeader) != "bob" || w.Header().Get(GroupsHeader) != "dev" {
+			t.Errorf("Token should be verified but got %d %v", w.Code, w.Header())
+		}
+	}
+	if resource != "/orders" {
+		t.Errorf("Authorizer should receive the forwarded path, got %q", resource)
+	}
+	if upstream.lookupCount() != 1 {
+		t.Errorf("Verified token should be cached, loginsrv was asked %d times", upstream.lookupCount())
+	}
+
+	if w := verify("X-Nothing", ""); w.Code != http.StatusUnauthorized || w.Header().Get(SubHeader) != "" {
+		t.Errorf("Missing token should be rejected but got %d %v", w.Code, w.Header())
+	}
+
+	req := httptest.NewRequest("GET", "/verify", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("X-Original-URI", "/admin")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Authorizer should deny the nginx original uri but got %d", w.Code)
+	}
+}
@@ -0,0 +1,185 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Gateway exposes attemptLogin as POST /login, refreshToken as POST /refresh
+// and getProfile as GET /profile to JSON clients without grpc,
+// the token is read from the Authorization header, mount it with http.StripPrefix
+type Gateway struct {
+	srv AuthServer
+
+	cookie       bool
+	cookieSecure bool
+
+	marshaler   *jsonpb.Marshaler
+	unmarshaler *jsonpb.Unmarshaler
+}
+
+// GatewayOption allows functional configuration for the Gateway
+type GatewayOption func(*Gateway)
+
+// WithGatewayCookie keeps the token in an HttpOnly jwt_token cookie like loginsrv does,
+// it is set by login and refresh and read when no Authorization header is sent
+func WithGatewayCookie(secure bool) GatewayOption {
+	return func(g *Gateway) {
+		g.cookie = true
+		g.cookieSecure = secure
+	}
+}
+
+// NewGateway creates a Gateway calling srv, usually a *LoginSrvServer
+func NewGateway(srv AuthServer, options ...GatewayOption) *Gateway {
+	g := &Gateway{
+		srv:         srv,
+		marshaler:   &jsonpb.Marshaler{EmitDefaults: true},
+		unmarshaler: &jsonpb.Unmarshaler{},
+	}
+
+	for i := range options {
+		options[i](g)
+	}
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/login":
+		g.handle(w, r, "POST", g.attemptLogin)
+	case "/refresh":
+		g.handle(w, r, "POST", g.refreshToken)
+	case "/profile":
+		g.handle(w, r, "GET", g.getProfile)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (g *Gateway) handle(w http.ResponseWriter, r *http.Request, method string, call func(http.ResponseWriter, *http.Request) (proto.Message, error)) {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		g.writeError(w, status.Errorf(codes.Unimplemented, "method %s not allowed", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+
+	reply, err := call(w, r)
+	if err != nil {
+		g.writeError(w, err, httpStatusFromCode(status.Code(err)))
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	g.marshaler.Marshal(w, reply)
+}
+
+func (g *Gateway) attemptLogin(w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	request := &LoginRequest{}
+	if err := g.unmarshaler.Unmarshal(r.Body, request); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid request body: %v", err)
+	}
+
+	reply, err := g.srv.AttemptLogin(r.Context(), request)
+	if err != nil {
+		return nil, err
+	}
+	g.setCookie(w, reply)
+	return reply, nil
+}
+
+func (g *Gateway) refreshToken(w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	reply, err := g.srv.RefreshToken(g.incomingContext(r), &RefreshRequest{})
+	if err != nil {
+		return nil, err
+	}
+	g.setCookie(w, reply)
+	return reply, nil
+}
+
+func (g *Gateway) getProfile(w http.ResponseWriter, r *http.Request) (proto.Message, error) {
+	return g.srv.GetProfile(g.incomingContext(r), &ProfileRequest{})
+}
+
+// incomingContext passes the token of the request as if it came with a grpc call
+func (g *Gateway) incomingContext(r *http.Request) context.Context {
+	cookieName := ""
+	if g.cookie {
+		cookieName = jwtCookieName
+	}
+	token := requestToken(r, cookieName)
+	if token == "" {
+		return r.Context()
+	}
+	return md.NewIncomingContext(r.Context(), md.Pairs(AuthTokenMetadataKey, "bearer "+token))
+}
+
+func (g *Gateway) setCookie(w http.ResponseWriter, reply *LoginReply) {
+	if !g.cookie {
+		return
+	}
+
+	cookie := &http.Cookie{
+		Name:     jwtCookieName,
+		Value:    reply.AccessToken,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   g.cookieSecure,
+	}
+	if reply.ExpiresAt > 0 {
+		cookie.Expires = time.Unix(reply.ExpiresAt, 0)
+	}
+	http.SetCookie(w, cookie)
+}
+
+func (g *Gateway) writeError(w http.ResponseWriter, err error, httpStatus int) {
+	s := status.Convert(err)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	json.NewEncoder(w).Encode(struct {
+		Code    codes.Code `json:"code"`
+		Message string     `json:"message"`
+	}{s.Code(), s.Message()})
+}
+
+// bearerToken returns the token of an Authorization header, if any
+func bearerToken(r *http.Request) string {
+	return bearerTokenOf(r.Header.Get("Authorization"))
+}
+
+// httpStatusFromCode maps a grpc code to the closest http status
+func httpStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
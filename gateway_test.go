@@ -0,0 +1,93 @@
+package loginsrv_grpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestGatewayLoginThenProfile(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	gateway := NewGateway(NewLoginSrvServer(upstream.URL))
+
+	w := httptest.NewRecorder()
+	gateway.ServeHTTP(w, httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"bob","password":"secret"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Login should succeed but got %d %s", w.Code, w.Body)
+	}
+	var reply struct {
+		AccessToken        string `json:"accessToken"`
+		RefreshesRemaining int    `json:"refreshesRemaining"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&reply); err != nil || reply.AccessToken == "" || reply.RefreshesRemaining != -1 {
+		t.Fatalf("Unexpected login reply %+v %v", reply, err)
+	}
+	if len(w.Result().Cookies()) != 0 {
+		t.Error("No cookie should be set unless enabled")
+	}
+
+	req := httptest.NewRequest("GET", "/profile", nil)
+	req.Header.Set("Authorization", "Bearer "+reply.AccessToken)
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"Sub":"bob"`) {
+		t.Errorf("Profile should be returned but got %d %s", w.Code, w.Body)
+	}
+}
+
+func TestGatewayMapsErrors(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	gateway := NewGateway(NewLoginSrvServer(upstream.URL))
+
+	for _, tc := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{"POST", "/login", `{"username":"bob","password":"wrong"}`, http.StatusForbidden},
+		{"POST", "/login", `not json`, http.StatusBadRequest},
+		{"GET", "/login", "", http.StatusMethodNotAllowed},
+		{"GET", "/profile", "", http.StatusUnauthorized},
+		{"POST", "/refresh", "", http.StatusUnauthorized},
+		{"GET", "/unknown", "", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		gateway.ServeHTTP(w, httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body)))
+		if w.Code != tc.status {
+			t.Errorf("%s %s should answer %d but got %d %s", tc.method, tc.path, tc.status, w.Code, w.Body)
+		}
+	}
+}
+
+func TestGatewayCookie(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	gateway := NewGateway(NewLoginSrvServer(upstream.URL), WithGatewayCookie(true))
+
+	w := httptest.NewRecorder()
+	gateway.ServeHTTP(w, httptest.NewRequest("POST", "/login", strings.NewReader(`{"username":"bob","password":"secret"}`)))
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != jwtCookieName || !cookies[0].HttpOnly || !cookies[0].Secure || cookies[0].Expires.IsZero() {
+		t.Fatalf("Login should set a secure HttpOnly cookie but got %v", cookies)
+	}
+
+	req := httptest.NewRequest("POST", "/refresh", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, req)
+	refreshed := w.Result().Cookies()
+	if w.Code != http.StatusOK || len(refreshed) != 1 || refreshed[0].Value == cookies[0].Value {
+		t.Fatalf("Refresh should replace the cookie but got %d %v", w.Code, refreshed)
+	}
+
+	req = httptest.NewRequest("GET", "/profile", nil)
+	req.AddCookie(refreshed[0])
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Profile should be read with the cookie but got %d %s", w.Code, w.Body)
+	}
+}
@@ -4,5 +4,6 @@ set -e
 set -x
 
 protoc -I ./ ./loginsrv.proto --go_out=plugins=grpc:.
+protoc -I ./ ./v1/loginsrv.proto --go_out=plugins=grpc,paths=source_relative:.
 
 echo "Items generrated"
\ No newline at end of file
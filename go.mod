@@ -3,7 +3,11 @@ module github.com/motia/loginsrv-grpc
 go 1.13
 
 require (
+	github.com/envoyproxy/go-control-plane v0.9.5
 	github.com/golang/protobuf v1.3.2
 	github.com/grpc-ecosystem/go-grpc-middleware v1.1.0
+	golang.org/x/net v0.0.0-20190311183353-d8887717615a
+	golang.org/x/oauth2 v0.0.0-20180821212333-d2e6202438be
+	google.golang.org/genproto v0.0.0-20190819201941-24fa4b261c55
 	google.golang.org/grpc v1.25.1
 )
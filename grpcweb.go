@@ -0,0 +1,312 @@
+package loginsrv_grpc
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/http2"
+)
+
+const (
+	grpcWebContentType     = "application/grpc-web"
+	grpcWebTextContentType = "application/grpc-web-text"
+
+	// grpcWebTrailerFlag marks the frame carrying the trailers at the end of the body
+	grpcWebTrailerFlag = 0x80
+)
+
+// grpcWebExposedHeaders are readable by browser clients of another origin
+var grpcWebExposedHeaders = []string{"grpc-status", "grpc-message", "grpc-status-details-bin", "set-cookie"}
+
+// GRPCWebHandler lets browsers call a grpc server with the grpc-web protocol,
+// in binary and text mode, over http/1.1 or http/2
+// the jwt_token cookie, or the one named with WithGRPCWebCookie, is sent as the authorization metadata
+// when the call has none, so Authenticate honours cookie based sessions
+type GRPCWebHandler struct {
+	server     http.Handler
+	cookieName string
+
+	allowedOrigins   map[string]bool
+	allowAnyOrigin   bool
+	allowCredentials bool
+	allowedHeaders   []string
+	maxAge           time.Duration
+}
+
+// GRPCWebOption allows functional configuration for the GRPCWebHandler
+type GRPCWebOption func(*GRPCWebHandler)
+
+// WithAllowedOrigins allows cross origin calls from the origins, "*" allows any origin
+// without credentials, without it only same origin calls are possible
+func WithAllowedOrigins(origins ...string) GRPCWebOption {
+	return func(h *GRPCWebHandler) {
+		for _, origin := range origins {
+			if origin == "*" {
+				h.allowAnyOrigin = true
+				continue
+			}
+			h.allowedOrigins[origin] = true
+		}
+	}
+}
+
+// WithAllowCredentials lets cross origin calls of the listed origins carry cookies,
+// origins only allowed by "*" never do
+func WithAllowCredentials() GRPCWebOption {
+	return func(h *GRPCWebHandler) {
+		h.allowCredentials = true
+	}
+}
+
+// WithAllowedHeaders adds request headers cross origin calls can send as metadata,
+// authorization and the grpc-web headers are always allowed
+func WithAllowedHeaders(headers ...string) GRPCWebOption {
+	return func(h *GRPCWebHandler) {
+		h.allowedHeaders = append(h.allowedHeaders, headers...)
+	}
+}
+
+// WithGRPCWebCookie sets the cookie sent as the authorization metadata, it should match WithCookieToken
+// as the authorization metadata takes precedence over the cookie read by Authenticate,
+// an empty name sends no authorization and leaves the cookie metadata to WithCookieToken
+func WithGRPCWebCookie(name string) GRPCWebOption {
+	return func(h *GRPCWebHandler) {
+		h.cookieName = name
+	}
+}
+
+// WithPreflightMaxAge sets how long browsers cache a preflight response, the default is 10 minutes
+func WithPreflightMaxAge(d time.Duration) GRPCWebOption {
+	return func(h *GRPCWebHandler) {
+		h.maxAge = d
+	}
+}
+
+// NewGRPCWebHandler wraps server, usually a *grpc.Server,
+// native grpc calls over http/2 are passed through untouched
+func NewGRPCWebHandler(server http.Handler, options ...GRPCWebOption) *GRPCWebHandler {
+	h := &GRPCWebHandler{
+		server:         server,
+		cookieName:     jwtCookieName,
+		allowedOrigins: map[string]bool{},
+		allowedHeaders: []string{"authorization", "content-type", "grpc-timeout", "x-grpc-web", "x-user-agent"},
+		maxAge:         10 * time.Minute,
+	}
+
+	for i := range options {
+		options[i](h)
+	}
+	return h
+}
+
+// IsGRPCWebRequest tells whether r is a grpc-web call or its cors preflight
+func IsGRPCWebRequest(r *http.Request) bool {
+	if r.Method == "OPTIONS" {
+		return r.Header.Get("Access-Control-Request-Method") != "" && isGRPCWebPreflight(r)
+	}
+	return r.Method == "POST" && strings.HasPrefix(r.Header.Get("Content-Type"), grpcWebContentType)
+}
+
+func (h *GRPCWebHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch {
+	case r.Method == "OPTIONS" && r.Header.Get("Access-Control-Request-Method") != "":
+		h.servePreflight(w, r)
+	case IsGRPCWebRequest(r):
+		h.serveGRPCWeb(w, r)
+	case r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc"):
+		h.server.ServeHTTP(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *GRPCWebHandler) servePreflight(w http.ResponseWriter, r *http.Request) {
+	if !h.setCORSHeaders(w, r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Methods", "POST")
+	w.Header().Set("Access-Control-Allow-Headers", strings.Join(h.allowedHeaders, ", "))
+	w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(h.maxAge.Seconds())))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// setCORSHeaders tells whether the origin of r may call the server,
+// same origin calls need no cors headers
+func (h *GRPCWebHandler) setCORSHeaders(w http.ResponseWriter, r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || isSameOrigin(r, origin) {
+		return true
+	}
+	if !h.allowedOrigins[origin] {
+		if !h.allowAnyOrigin {
+			return false
+		}
+		// reflecting any origin along with credentials would let any site call as the user
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(grpcWebExposedHeaders, ", "))
+		return true
+	}
+
+	w.Header().Add("Vary", "Origin")
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Expose-Headers", strings.Join(grpcWebExposedHeaders, ", "))
+	if h.allowCredentials {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	return true
+}
+
+// cookieAllowed tells whether the cookie of r may authorize the call,
+// only same origin calls and the listed origins qualify
+func (h *GRPCWebHandler) cookieAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	return origin == "" || isSameOrigin(r, origin) || h.allowedOrigins[origin]
+}
+
+func (h *GRPCWebHandler) serveGRPCWeb(w http.ResponseWriter, r *http.Request) {
+	if !h.setCORSHeaders(w, r) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	contentType := r.Header.Get("Content-Type")
+	text := strings.HasPrefix(contentType, grpcWebTextContentType)
+
+	req := r.Clone(r.Context())
+	req.ProtoMajor, req.ProtoMinor, req.Proto = 2, 0, "HTTP/2.0"
+	req.ContentLength = -1
+	req.Header.Del("Content-Length")
+	req.Header.Set("Content-Type", grpcContentType(contentType, text))
+	if text {
+		req.Body = ioutil.NopCloser(base64.NewDecoder(base64.StdEncoding, r.Body))
+	}
+	if req.Header.Get("Authorization") == "" && h.cookieName != "" && h.cookieAllowed(r) {
+		if cookie, err := r.Cookie(h.cookieName); err == nil {
+			req.Header.Set("Authorization", "bearer "+cookie.Value)
+		}
+	}
+
+	resp := newGRPCWebResponse(w, contentType, text)
+	h.server.ServeHTTP(resp, req)
+	resp.finish()
+}
+
+// grpcContentType returns the native grpc content type of a grpc-web one
+func grpcContentType(contentType string, text bool) string {
+	if text {
+		return "application/grpc" + strings.TrimPrefix(contentType, grpcWebTextContentType)
+	}
+	return "application/grpc" + strings.TrimPrefix(contentType, grpcWebContentType)
+}
+
+// isSameOrigin tells whether the origin, sent by browsers on every POST, is the one of the server
+func isSameOrigin(r *http.Request, origin string) bool {
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return u.Scheme == scheme && strings.EqualFold(u.Host, r.Host)
+}
+
+func isGRPCWebPreflight(r *http.Request) bool {
+	for _, header := range strings.Split(r.Header.Get("Access-Control-Request-Headers"), ",") {
+		if strings.TrimSpace(strings.ToLower(header)) == "x-grpc-web" {
+			return true
+		}
+	}
+	return false
+}
+
+// grpcWebResponse moves the trailers written by the grpc server
+// to a frame at the end of the body, browsers cannot read http trailers
+type grpcWebResponse struct {
+	w           http.ResponseWriter
+	header      http.Header
+	contentType string
+	text        bool
+	wroteHeader bool
+}
+
+func newGRPCWebResponse(w http.ResponseWriter, contentType string, text bool) *grpcWebResponse {
+	return &grpcWebResponse{w: w, header: http.Header{}, contentType: contentType, text: text}
+}
+
+func (resp *grpcWebResponse) Header() http.Header {
+	return resp.header
+}
+
+func (resp *grpcWebResponse) WriteHeader(code int) {
+	if resp.wroteHeader {
+		return
+	}
+	resp.wroteHeader = true
+
+	for name, values := range resp.header {
+		if name == "Trailer" || name == "Content-Type" || isGRPCWebTrailer(name) {
+			continue
+		}
+		resp.w.Header()[name] = values
+	}
+	resp.w.Header().Set("Content-Type", resp.contentType)
+	resp.w.WriteHeader(code)
+}
+
+func (resp *grpcWebResponse) Write(data []byte) (int, error) {
+	resp.WriteHeader(http.StatusOK)
+	if resp.text {
+		if _, err := io.WriteString(resp.w, base64.StdEncoding.EncodeToString(data)); err != nil {
+			return 0, err
+		}
+		return len(data), nil
+	}
+	return resp.w.Write(data)
+}
+
+func (resp *grpcWebResponse) Flush() {
+	resp.WriteHeader(http.StatusOK)
+	if flusher, ok := resp.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// finish writes the trailer frame
+func (resp *grpcWebResponse) finish() {
+	var trailers bytes.Buffer
+	for name, values := range resp.header {
+		if !isGRPCWebTrailer(name) {
+			continue
+		}
+		name = strings.ToLower(strings.TrimPrefix(name, http2.TrailerPrefix))
+		for _, value := range values {
+			trailers.WriteString(name + ": " + value + "\r\n")
+		}
+	}
+
+	frame := make([]byte, 5, 5+trailers.Len())
+	frame[0] = grpcWebTrailerFlag
+	binary.BigEndian.PutUint32(frame[1:], uint32(trailers.Len()))
+	resp.Write(append(frame, trailers.Bytes()...))
+	resp.Flush()
+}
+
+func isGRPCWebTrailer(name string) bool {
+	switch name {
+	case "Grpc-Status", "Grpc-Message", "Grpc-Status-Details-Bin":
+		return true
+	}
+	return strings.HasPrefix(name, http2.TrailerPrefix)
+}
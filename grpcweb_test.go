@@ -0,0 +1,233 @@
+package loginsrv_grpc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func newGRPCWebTestHandler(srv *LoginSrvServer, options ...GRPCWebOption) *GRPCWebHandler {
+	server := grpc.NewServer(grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(srv.Authenticate)))
+	RegisterAuthServer(server, srv)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	return NewGRPCWebHandler(server, options...)
+}
+
+// grpcWebCall sends a unary grpc-web call and returns the reply message and the trailers
+func grpcWebCall(t *testing.T, h http.Handler, contentType string, method string, request proto.Message, cookies ...*http.Cookie) ([]byte, map[string]string) {
+	data, err := proto.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := append([]byte{0, 0, 0, 0, 0}, data...)
+	binary.BigEndian.PutUint32(body[1:], uint32(len(data)))
+
+	text := strings.HasPrefix(contentType, grpcWebTextContentType)
+	if text {
+		body = []byte(base64.StdEncoding.EncodeToString(body))
+	}
+	req := httptest.NewRequest("POST", method, bytes.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Grpc-Web", "1")
+	for _, cookie := range cookies {
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != contentType {
+		t.Fatalf("Unexpected grpc-web response %d %v", w.Code, w.Header())
+	}
+
+	raw := w.Body.Bytes()
+	if text {
+		// every write is encoded on its own, padding included, so groups are decoded one by one
+		var decoded []byte
+		for i := 0; i+4 <= len(raw); i += 4 {
+			group, err := base64.StdEncoding.DecodeString(string(raw[i : i+4]))
+			if err != nil {
+				t.Fatal(err)
+			}
+			decoded = append(decoded, group...)
+		}
+		raw = decoded
+	}
+
+	var message []byte
+	trailers := map[string]string{}
+	for len(raw) >= 5 {
+		flag, size := raw[0], binary.BigEndian.Uint32(raw[1:5])
+		frame := raw[5 : 5+size]
+		raw = raw[5+size:]
+		if flag&grpcWebTrailerFlag == 0 {
+			message = frame
+			continue
+		}
+		scanner := bufio.NewScanner(bytes.NewReader(frame))
+		for scanner.Scan() {
+			segs := strings.SplitN(scanner.Text(), ": ", 2)
+			trailers[segs[0]] = segs[1]
+		}
+	}
+	return message, trailers
+}
+
+func TestGRPCWebBinaryLogin(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	h := newGRPCWebTestHandler(NewLoginSrvServer(upstream.URL))
+
+	message, trailers := grpcWebCall(t, h, "application/grpc-web+proto", "/loginsrv_grpc.Auth/attemptLogin",
+		&LoginRequest{Username: "bob", Password: "secret"}, nil)
+	if trailers["grpc-status"] != "0" {
+		t.Fatalf("Login should succeed but got %v", trailers)
+	}
+	reply := &LoginReply{}
+	if err := proto.Unmarshal(message, reply); err != nil || reply.Profile.GetSub() != "bob" {
+		t.Errorf("Unexpected login reply %v %v", reply, err)
+	}
+
+	_, trailers = grpcWebCall(t, h, "application/grpc-web", "/loginsrv_grpc.Auth/attemptLogin",
+		&LoginRequest{Username: "bob", Password: "wrong"}, nil)
+	if trailers["grpc-status"] != "7" || trailers["grpc-message"] == "" {
+		t.Errorf("Wrong credentials should be denied but got %v", trailers)
+	}
+}
+
+func TestGRPCWebTextHonoursCookie(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	h := newGRPCWebTestHandler(srv)
+	cookie := &http.Cookie{Name: jwtCookieName, Value: obtainTokenOrFail(t, srv)}
+
+	_, trailers := grpcWebCall(t, h, "application/grpc-web-text", "/grpc.health.v1.Health/Check", &healthpb.HealthCheckRequest{}, nil)
+	if trailers["grpc-status"] != "16" {
+		t.Errorf("Authenticate should reject a call without token but got %v", trailers)
+	}
+
+	message, trailers := grpcWebCall(t, h, "application/grpc-web-text", "/grpc.health.v1.Health/Check", &healthpb.HealthCheckRequest{}, cookie)
+	if trailers["grpc-status"] != "0" {
+		t.Fatalf("Authenticate should accept the cookie but got %v", trailers)
+	}
+	reply := &healthpb.HealthCheckResponse{}
+	if err := proto.Unmarshal(message, reply); err != nil || reply.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Unexpected health reply %v %v", reply, err)
+	}
+
+	message, trailers = grpcWebCall(t, h, "application/grpc-web-text+proto", "/loginsrv_grpc.Auth/getProfile", &ProfileRequest{}, cookie)
+	profile := &Profile{}
+	if err := proto.Unmarshal(message, profile); trailers["grpc-status"] != "0" || err != nil || profile.Sub != "bob" {
+		t.Errorf("Profile should be read with the cookie but got %v %v", trailers, profile)
+	}
+}
+
+func TestGRPCWebHonoursNamedCookie(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithCookieToken("session"))
+	session := &http.Cookie{Name: "session", Value: obtainTokenOrFail(t, srv)}
+	stale := &http.Cookie{Name: jwtCookieName, Value: "stale"}
+
+	for _, h := range []*GRPCWebHandler{
+		newGRPCWebTestHandler(srv, WithGRPCWebCookie("session")),
+		newGRPCWebTestHandler(srv, WithGRPCWebCookie("")),
+	} {
+		message, trailers := grpcWebCall(t, h, "application/grpc-web", "/loginsrv_grpc.Auth/getProfile", &ProfileRequest{}, stale, session)
+		profile := &Profile{}
+		if err := proto.Unmarshal(message, profile); trailers["grpc-status"] != "0" || err != nil || profile.Sub != "bob" {
+			t.Errorf("Profile should be read with the named cookie but got %v %v", trailers, profile)
+		}
+	}
+}
+
+func TestGRPCWebAllowsSameOrigin(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	h := newGRPCWebTestHandler(NewLoginSrvServer(upstream.URL))
+
+	call := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/grpc.health.v1.Health/Check", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+		req.Host = "auth.example.com"
+		req.Header.Set("Content-Type", grpcWebContentType)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := call("http://auth.example.com"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Same origin call should pass without cors headers but got %d %v", w.Code, w.Header())
+	}
+	if w := call("https://auth.example.com"); w.Code != http.StatusForbidden {
+		t.Errorf("Origin with another scheme should be rejected but got %d", w.Code)
+	}
+}
+
+func TestGRPCWebCORS(t *testing.T) {
+	h := NewGRPCWebHandler(grpc.NewServer(), WithAllowedOrigins("https://app.example.com"), WithAllowCredentials())
+
+	preflight := func(origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("OPTIONS", "/loginsrv_grpc.Auth/attemptLogin", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		req.Header.Set("Access-Control-Request-Headers", "content-type,x-grpc-web")
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := preflight("https://app.example.com")
+	if w.Code != http.StatusNoContent ||
+		w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		w.Header().Get("Access-Control-Allow-Credentials") != "true" ||
+		!strings.Contains(w.Header().Get("Access-Control-Allow-Headers"), "authorization") ||
+		!strings.Contains(w.Header().Get("Access-Control-Expose-Headers"), "grpc-status") {
+		t.Errorf("Preflight of an allowed origin should pass but got %d %v", w.Code, w.Header())
+	}
+
+	if w = preflight("https://evil.example.com"); w.Code != http.StatusForbidden || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("Preflight of another origin should fail but got %d %v", w.Code, w.Header())
+	}
+
+	req := httptest.NewRequest("GET", "/", nil)
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Other requests should not be served but got %d", w.Code)
+	}
+}
+
+func TestGRPCWebAnyOriginGetsNoCredentials(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	h := newGRPCWebTestHandler(srv, WithAllowedOrigins("*"), WithAllowCredentials())
+
+	req := httptest.NewRequest("POST", "/grpc.health.v1.Health/Check", bytes.NewReader([]byte{0, 0, 0, 0, 0}))
+	req.Header.Set("Content-Type", grpcWebContentType)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.AddCookie(&http.Cookie{Name: jwtCookieName, Value: obtainTokenOrFail(t, srv)})
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("Any origin should get a wildcard without credentials but got %v", w.Header())
+	}
+	if !bytes.Contains(w.Body.Bytes(), []byte("grpc-status: 16")) {
+		t.Errorf("Cookie of a call from any origin should not authorize it but got %q", w.Body.String())
+	}
+}
@@ -0,0 +1,175 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// AuthServiceName is the name of the Auth service in health checks
+const AuthServiceName = "loginsrv_grpc.Auth"
+
+// HealthChecker keeps a grpc.health.v1 server updated with the reachability of loginsrv
+// both the overall server and the Auth service are reported
+type HealthChecker struct {
+	srv    *LoginSrvServer
+	health *health.Server
+	client *http.Client
+
+	interval         time.Duration
+	failureThreshold int
+	successThreshold int
+
+	mu        sync.Mutex
+	serving   bool
+	failures  int
+	successes int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// HealthOption allows functional configuration for the HealthChecker
+type HealthOption func(*HealthChecker)
+
+// WithProbeInterval sets the time between two probes of loginsrv, the default is 10 seconds
+func WithProbeInterval(d time.Duration) HealthOption {
+	return func(h *HealthChecker) {
+		h.interval = d
+	}
+}
+
+// WithProbeTimeout bounds a single probe of loginsrv, the default is 2 seconds
+func WithProbeTimeout(d time.Duration) HealthOption {
+	return func(h *HealthChecker) {
+		h.client.Timeout = d
+	}
+}
+
+// WithFailureThreshold sets the consecutive failed probes reporting NOT_SERVING, the default is 3
+func WithFailureThreshold(n int) HealthOption {
+	return func(h *HealthChecker) {
+		h.failureThreshold = n
+	}
+}
+
+// WithSuccessThreshold sets the consecutive successful probes reporting SERVING, the default is 1
+func WithSuccessThreshold(n int) HealthOption {
+	return func(h *HealthChecker) {
+		h.successThreshold = n
+	}
+}
+
+// NewHealthChecker creates a HealthChecker reporting NOT_SERVING until loginsrv answers
+func NewHealthChecker(srv *LoginSrvServer, options ...HealthOption) *HealthChecker {
+	client := *srv.noRedirectClient()
+	client.Timeout = 2 * time.Second
+
+	h := &HealthChecker{
+		srv:              srv,
+		health:           health.NewServer(),
+		client:           &client,
+		interval:         10 * time.Second,
+		failureThreshold: 3,
+		successThreshold: 1,
+		stop:             make(chan struct{}),
+	}
+
+	for i := range options {
+		options[i](h)
+	}
+	h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+	return h
+}
+
+// Server returns the health server to register with healthpb.RegisterHealthServer,
+// it skips Authenticate so probes without a token reach it
+func (h *HealthChecker) Server() healthpb.HealthServer {
+	return healthServer{h.health}
+}
+
+// healthServer lets the health checks through the grpc_auth interceptors
+type healthServer struct {
+	*health.Server
+}
+
+// AuthFuncOverride skips authentication, health probes carry no token
+func (healthServer) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	return ctx, nil
+}
+
+// Start probes loginsrv in the background until Stop
+func (h *HealthChecker) Start() {
+	go func() {
+		ticker := time.NewTicker(h.interval)
+		defer ticker.Stop()
+
+		for {
+			h.Probe()
+			select {
+			case <-h.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends the background probes
+func (h *HealthChecker) Stop() {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+}
+
+// Probe checks loginsrv once and updates the health server when a threshold is reached
+// it returns whether loginsrv answered
+func (h *HealthChecker) Probe() bool {
+	ok := h.probe()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if ok {
+		h.successes++
+		h.failures = 0
+		if !h.serving && h.successes >= h.successThreshold {
+			h.serving = true
+			h.setServingStatus(healthpb.HealthCheckResponse_SERVING)
+		}
+	} else {
+		h.failures++
+		h.successes = 0
+		if h.serving && h.failures >= h.failureThreshold {
+			h.serving = false
+			h.setServingStatus(healthpb.HealthCheckResponse_NOT_SERVING)
+		}
+	}
+	return ok
+}
+
+// probe tells whether loginsrv answers on its login endpoint,
+// any response below 500 means it is up
+func (h *HealthChecker) probe() bool {
+	req, err := http.NewRequest("GET", *h.srv.baseURL+"/login", nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode < 500
+}
+
+func (h *HealthChecker) setServingStatus(status healthpb.HealthCheckResponse_ServingStatus) {
+	h.health.SetServingStatus("", status)
+	h.health.SetServingStatus(AuthServiceName, status)
+}
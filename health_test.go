@@ -0,0 +1,104 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestHealthCheckerFollowsThresholds(t *testing.T) {
+	var down int32
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&down) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer upstream.Close()
+
+	checker := NewHealthChecker(NewLoginSrvServer(upstream.URL), WithFailureThreshold(2), WithSuccessThreshold(2))
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_NOT_SERVING)
+
+	checker.Probe()
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_NOT_SERVING)
+	checker.Probe()
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_SERVING)
+
+	atomic.StoreInt32(&down, 1)
+	if checker.Probe() {
+		t.Error("Probe should fail while loginsrv errors")
+	}
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_SERVING)
+	checker.Probe()
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func TestHealthCheckerReportsUnreachableLoginsrv(t *testing.T) {
+	upstream := httptest.NewServer(http.NotFoundHandler())
+	url := upstream.URL
+	upstream.Close()
+
+	checker := NewHealthChecker(NewLoginSrvServer(url), WithFailureThreshold(1))
+	if checker.Probe() {
+		t.Error("Probe should fail when loginsrv is unreachable")
+	}
+	assertServingStatus(t, checker, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+func TestHealthCheckSkipsAuthenticate(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	checker := NewHealthChecker(srv)
+	checker.Probe()
+
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(srv.Authenticate)),
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(srv.Authenticate)),
+	)
+	healthpb.RegisterHealthServer(server, checker.Server())
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	defer server.Stop()
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{Service: AuthServiceName})
+	if err != nil {
+		t.Fatal("Health check without token should pass Authenticate", err)
+	}
+	if resp.Status != healthpb.HealthCheckResponse_SERVING {
+		t.Errorf("Expected SERVING but got %v", resp.Status)
+	}
+}
+
+func assertServingStatus(t *testing.T, checker *HealthChecker, expected healthpb.HealthCheckResponse_ServingStatus) {
+	for _, service := range []string{"", AuthServiceName} {
+		resp, err := checker.Server().Check(nil, &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			t.Fatal("Check failed", err)
+		}
+		if resp.Status != expected {
+			t.Errorf("Expected %v for %q but got %v", expected, service, resp.Status)
+		}
+	}
+}
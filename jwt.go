@@ -0,0 +1,57 @@
+package loginsrv_grpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"hash"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when the signature of a token does not match its content
+var ErrInvalidSignature = errors.New("invalid signature")
+
+var hmacAlgorithms = map[string]func() hash.Hash{
+	"HS256": sha256.New,
+	"HS384": sha512.New384,
+	"HS512": sha512.New,
+}
+
+// verifyToken returns the claims of a token signed by loginsrv with the shared secret
+func verifyToken(token string, secret []byte) (*Claims, error) {
+	segs := strings.Split(token, ".")
+	if len(segs) != 3 {
+		return nil, ErrMalformedToken
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(segs[0])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+	header := struct {
+		Algorithm string `json:"alg"`
+	}{}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	newHash, ok := hmacAlgorithms[header.Algorithm]
+	if !ok {
+		return nil, ErrInvalidSignature
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(segs[2])
+	if err != nil {
+		return nil, ErrMalformedToken
+	}
+
+	mac := hmac.New(newHash, secret)
+	mac.Write([]byte(segs[0] + "." + segs[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidSignature
+	}
+
+	return ParseUnverifiedClaims(token)
+}
@@ -0,0 +1,45 @@
+package loginsrv_grpc
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVerifyToken(t *testing.T) {
+	secret := []byte("my_secret")
+	token := signedTestToken(secret, map[string]interface{}{"sub": "bob", "groups": []string{"dev"}})
+
+	claims, err := verifyToken(token, secret)
+	if err != nil {
+		t.Fatal("Token should be valid", err)
+	}
+	if claims.Sub != "bob" {
+		t.Error("Expected 'bob' claims but got " + claims.Sub)
+	}
+
+	segs := strings.Split(token, ".")
+	forged := segs[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"admin"}`)) + "." + segs[2]
+	for _, token := range []string{forged, unsignedTestToken(map[string]interface{}{"sub": "bob"})} {
+		if _, err := verifyToken(token, secret); err != ErrInvalidSignature {
+			t.Errorf("Token %q should be rejected but got %v", token, err)
+		}
+	}
+	if _, err := verifyToken(token, []byte("other")); err != ErrInvalidSignature {
+		t.Error("Token signed with another secret should be rejected", err)
+	}
+}
+
+// signedTestToken signs the claims with HS512 like loginsrv does by default
+func signedTestToken(secret []byte, claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "HS512", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	content := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	mac := hmac.New(sha512.New, secret)
+	mac.Write([]byte(content))
+	return content + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -24,12 +24,83 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
 
+type LoginProvider_Flow int32
+
+const (
+	// username and password sent with attemptLogin
+	LoginProvider_PASSWORD LoginProvider_Flow = 0
+	// user agent redirected with startOAuthLogin
+	LoginProvider_REDIRECT LoginProvider_Flow = 1
+)
+
+var LoginProvider_Flow_name = map[int32]string{
+	0: "PASSWORD",
+	1: "REDIRECT",
+}
+
+var LoginProvider_Flow_value = map[string]int32{
+	"PASSWORD": 0,
+	"REDIRECT": 1,
+}
+
+func (x LoginProvider_Flow) String() string {
+	return proto.EnumName(LoginProvider_Flow_name, int32(x))
+}
+
+func (LoginProvider_Flow) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{16, 0}
+}
+
+type SessionEvent_Type int32
+
+const (
+	// the token was refreshed, accessToken carries the new one
+	SessionEvent_TOKEN_REFRESHED SessionEvent_Type = 0
+	// the token can no longer be refreshed and expires at expiresAt
+	SessionEvent_EXPIRY_WARNING SessionEvent_Type = 1
+	// the token expired, the stream ends
+	SessionEvent_EXPIRED SessionEvent_Type = 2
+	// the token was revoked by a logout, the stream ends
+	SessionEvent_REVOKED SessionEvent_Type = 3
+	// the session was ended by the server, the stream ends
+	SessionEvent_FORCED_LOGOUT SessionEvent_Type = 4
+)
+
+var SessionEvent_Type_name = map[int32]string{
+	0: "TOKEN_REFRESHED",
+	1: "EXPIRY_WARNING",
+	2: "EXPIRED",
+	3: "REVOKED",
+	4: "FORCED_LOGOUT",
+}
+
+var SessionEvent_Type_value = map[string]int32{
+	"TOKEN_REFRESHED": 0,
+	"EXPIRY_WARNING":  1,
+	"EXPIRED":         2,
+	"REVOKED":         3,
+	"FORCED_LOGOUT":   4,
+}
+
+func (x SessionEvent_Type) String() string {
+	return proto.EnumName(SessionEvent_Type_name, int32(x))
+}
+
+func (SessionEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{18, 0}
+}
+
 type LoginRequest struct {
-	Username             string   `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
-	Password             string   `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// password provider declared on the server, the request is posted to /login/<provider>
+	// loginsrv still tries all of its password backends, the path does not select one
+	Provider string `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	// additional form fields, e.g. a domain or an otp
+	Extra                map[string]string `protobuf:"bytes,4,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
 }
 
 func (m *LoginRequest) Reset()         { *m = LoginRequest{} }
@@ -71,6 +142,20 @@ func (m *LoginRequest) GetPassword() string {
 	return ""
 }
 
+func (m *LoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *LoginRequest) GetExtra() map[string]string {
+	if m != nil {
+		return m.Extra
+	}
+	return nil
+}
+
 type RefreshRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -103,7 +188,14 @@ func (m *RefreshRequest) XXX_DiscardUnknown() {
 var xxx_messageInfo_RefreshRequest proto.InternalMessageInfo
 
 type LoginReply struct {
-	AccessToken          string   `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	AccessToken string `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	// unix time of the token expiry
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	// refreshes left for the token, -1 when unknown
+	RefreshesRemaining int32 `protobuf:"varint,3,opt,name=refreshesRemaining,proto3" json:"refreshesRemaining,omitempty"`
+	// always bearer
+	TokenType            string   `protobuf:"bytes,4,opt,name=tokenType,proto3" json:"tokenType,omitempty"`
+	Profile              *Profile `protobuf:"bytes,5,opt,name=profile,proto3" json:"profile,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -141,6 +233,96 @@ func (m *LoginReply) GetAccessToken() string {
 	return ""
 }
 
+func (m *LoginReply) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *LoginReply) GetRefreshesRemaining() int32 {
+	if m != nil {
+		return m.RefreshesRemaining
+	}
+	return 0
+}
+
+func (m *LoginReply) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
+	}
+	return ""
+}
+
+func (m *LoginReply) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+type LogoutRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogoutRequest) Reset()         { *m = LogoutRequest{} }
+func (m *LogoutRequest) String() string { return proto.CompactTextString(m) }
+func (*LogoutRequest) ProtoMessage()    {}
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{3}
+}
+
+func (m *LogoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutRequest.Unmarshal(m, b)
+}
+func (m *LogoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutRequest.Marshal(b, m, deterministic)
+}
+func (m *LogoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutRequest.Merge(m, src)
+}
+func (m *LogoutRequest) XXX_Size() int {
+	return xxx_messageInfo_LogoutRequest.Size(m)
+}
+func (m *LogoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogoutRequest proto.InternalMessageInfo
+
+type LogoutReply struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogoutReply) Reset()         { *m = LogoutReply{} }
+func (m *LogoutReply) String() string { return proto.CompactTextString(m) }
+func (*LogoutReply) ProtoMessage()    {}
+func (*LogoutReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{4}
+}
+
+func (m *LogoutReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutReply.Unmarshal(m, b)
+}
+func (m *LogoutReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutReply.Marshal(b, m, deterministic)
+}
+func (m *LogoutReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutReply.Merge(m, src)
+}
+func (m *LogoutReply) XXX_Size() int {
+	return xxx_messageInfo_LogoutReply.Size(m)
+}
+func (m *LogoutReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogoutReply proto.InternalMessageInfo
+
 type ProfileRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -151,7 +333,7 @@ func (m *ProfileRequest) Reset()         { *m = ProfileRequest{} }
 func (m *ProfileRequest) String() string { return proto.CompactTextString(m) }
 func (*ProfileRequest) ProtoMessage()    {}
 func (*ProfileRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ba74aec577d9b91b, []int{3}
+	return fileDescriptor_ba74aec577d9b91b, []int{5}
 }
 
 func (m *ProfileRequest) XXX_Unmarshal(b []byte) error {
@@ -191,7 +373,7 @@ func (m *Profile) Reset()         { *m = Profile{} }
 func (m *Profile) String() string { return proto.CompactTextString(m) }
 func (*Profile) ProtoMessage()    {}
 func (*Profile) Descriptor() ([]byte, []int) {
-	return fileDescriptor_ba74aec577d9b91b, []int{4}
+	return fileDescriptor_ba74aec577d9b91b, []int{6}
 }
 
 func (m *Profile) XXX_Unmarshal(b []byte) error {
@@ -275,190 +457,1267 @@ func (m *Profile) GetGroups() []string {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*LoginRequest)(nil), "loginsrv_grpc.LoginRequest")
-	proto.RegisterType((*RefreshRequest)(nil), "loginsrv_grpc.RefreshRequest")
-	proto.RegisterType((*LoginReply)(nil), "loginsrv_grpc.LoginReply")
-	proto.RegisterType((*ProfileRequest)(nil), "loginsrv_grpc.ProfileRequest")
-	proto.RegisterType((*Profile)(nil), "loginsrv_grpc.Profile")
+type ValidateTokenRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func init() { proto.RegisterFile("loginsrv.proto", fileDescriptor_ba74aec577d9b91b) }
+func (m *ValidateTokenRequest) Reset()         { *m = ValidateTokenRequest{} }
+func (m *ValidateTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokenRequest) ProtoMessage()    {}
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{7}
+}
 
-var fileDescriptor_ba74aec577d9b91b = []byte{
-	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x92, 0xdf, 0x4e, 0xc2, 0x30,
-	0x14, 0xc6, 0x99, 0xe3, 0xef, 0x11, 0x09, 0x39, 0x31, 0xa4, 0xa2, 0x26, 0xcb, 0xae, 0xb8, 0xda,
-	0x85, 0x3e, 0x81, 0x89, 0xa8, 0x31, 0x46, 0xc9, 0xf4, 0xde, 0x8c, 0x59, 0x46, 0xe3, 0xb6, 0xd6,
-	0xb6, 0x53, 0x79, 0x58, 0xaf, 0x7c, 0x11, 0xd3, 0xad, 0x53, 0x20, 0xc4, 0xbb, 0xfe, 0x7e, 0x87,
-	0x7e, 0x39, 0xf4, 0x1b, 0x0c, 0x52, 0x9e, 0xb0, 0x5c, 0xc9, 0xf7, 0x40, 0x48, 0xae, 0x39, 0x1e,
-	0xd4, 0xfc, 0x9c, 0x48, 0x11, 0xfb, 0x57, 0xd0, 0xbf, 0x33, 0x22, 0xa4, 0x6f, 0x05, 0x55, 0x1a,
-	0xc7, 0xd0, 0x2d, 0x14, 0x95, 0x79, 0x94, 0x51, 0xe2, 0x78, 0xce, 0xa4, 0x17, 0xfe, 0xb2, 0x99,
-	0x89, 0x48, 0xa9, 0x0f, 0x2e, 0x5f, 0xc8, 0x5e, 0x35, 0xab, 0xd9, 0x1f, 0xc2, 0x20, 0xa4, 0x0b,
-	0x49, 0xd5, 0xd2, 0x26, 0xf9, 0x01, 0x80, 0x4d, 0x16, 0xe9, 0x0a, 0x3d, 0xd8, 0x8f, 0xe2, 0x98,
-	0x2a, 0xf5, 0xc4, 0x5f, 0x69, 0x6e, 0xa3, 0xd7, 0x95, 0x49, 0x98, 0x49, 0xbe, 0x60, 0x29, 0xad,
-	0x13, 0xbe, 0x1c, 0xe8, 0x58, 0x85, 0x43, 0x70, 0x1f, 0x8b, 0xb9, 0xbd, 0x67, 0x8e, 0x48, 0xa0,
-	0x33, 0x63, 0xb1, 0x2e, 0x24, 0xb5, 0xcb, 0xd4, 0x88, 0x08, 0xcd, 0x7b, 0xb3, 0xbf, 0x5b, 0xea,
-	0xf2, 0x8c, 0x87, 0xd0, 0x9a, 0x66, 0x11, 0x4b, 0x49, 0xb3, 0x94, 0x15, 0xe0, 0x08, 0xda, 0x0f,
-	0x92, 0x25, 0x2c, 0x27, 0xad, 0x52, 0x5b, 0x32, 0x7e, 0xfa, 0x29, 0x98, 0x5c, 0x91, 0xb6, 0xe7,
-	0x4c, 0xdc, 0xd0, 0x12, 0x9e, 0x40, 0xcf, 0xfe, 0x4b, 0xaa, 0x48, 0xc7, 0x73, 0x26, 0xad, 0xf0,
-	0x4f, 0x98, 0x5b, 0x97, 0x3c, 0x8b, 0x58, 0x4e, 0xba, 0x55, 0x5a, 0x45, 0xc6, 0x5f, 0x4b, 0x5e,
-	0x08, 0x45, 0x7a, 0x9e, 0x6b, 0x7c, 0x45, 0x67, 0xdf, 0x0e, 0x34, 0x2f, 0x0a, 0xbd, 0xc4, 0x1b,
-	0xe8, 0x47, 0x5a, 0xd3, 0x4c, 0xe8, 0xf2, 0xc5, 0xf0, 0x38, 0xd8, 0x28, 0x29, 0x58, 0x6f, 0x68,
-	0x7c, 0xb4, 0x7b, 0x28, 0xd2, 0x95, 0xdf, 0xc0, 0x5b, 0xe8, 0xcb, 0x6a, 0x9f, 0xf2, 0x51, 0xf1,
-	0x74, 0xeb, 0xc7, 0x9b, 0x1d, 0xfd, 0x9f, 0x35, 0x05, 0x48, 0xa8, 0xae, 0x0b, 0xd8, 0x4e, 0xda,
-	0xec, 0x6a, 0x3c, 0xda, 0x3d, 0xf6, 0x1b, 0xf3, 0x76, 0xf9, 0xdd, 0x9d, 0xff, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x35, 0x67, 0xca, 0xdf, 0x89, 0x02, 0x00, 0x00,
+func (m *ValidateTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokenRequest.Unmarshal(m, b)
+}
+func (m *ValidateTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokenRequest.Merge(m, src)
+}
+func (m *ValidateTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokenRequest.Size(m)
+}
+func (m *ValidateTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokenRequest.DiscardUnknown(m)
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+var xxx_messageInfo_ValidateTokenRequest proto.InternalMessageInfo
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *ValidateTokenRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
 
-// AuthClient is the client API for Auth service.
-//
-// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
-type AuthClient interface {
-	AttemptLogin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error)
-	RefreshToken(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginReply, error)
-	GetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+type ValidateTokenReply struct {
+	Active  bool     `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Profile *Profile `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	// seconds left before the token expires
+	ExpiresIn int64 `protobuf:"varint,3,opt,name=expiresIn,proto3" json:"expiresIn,omitempty"`
+	// why the token is not active
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// why the token could not be checked, e.g. loginsrv is unreachable,
+	// only set in the results of validateTokens
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-type authClient struct {
-	cc *grpc.ClientConn
+func (m *ValidateTokenReply) Reset()         { *m = ValidateTokenReply{} }
+func (m *ValidateTokenReply) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokenReply) ProtoMessage()    {}
+func (*ValidateTokenReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{8}
 }
 
-func NewAuthClient(cc *grpc.ClientConn) AuthClient {
-	return &authClient{cc}
+func (m *ValidateTokenReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokenReply.Unmarshal(m, b)
+}
+func (m *ValidateTokenReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokenReply.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokenReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokenReply.Merge(m, src)
+}
+func (m *ValidateTokenReply) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokenReply.Size(m)
+}
+func (m *ValidateTokenReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokenReply.DiscardUnknown(m)
 }
 
-func (c *authClient) AttemptLogin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error) {
-	out := new(LoginReply)
-	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/attemptLogin", in, out, opts...)
-	if err != nil {
-		return nil, err
+var xxx_messageInfo_ValidateTokenReply proto.InternalMessageInfo
+
+func (m *ValidateTokenReply) GetActive() bool {
+	if m != nil {
+		return m.Active
 	}
-	return out, nil
+	return false
 }
 
-func (c *authClient) RefreshToken(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginReply, error) {
-	out := new(LoginReply)
-	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/refreshToken", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateTokenReply) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
 	}
-	return out, nil
+	return nil
 }
 
-func (c *authClient) GetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
-	out := new(Profile)
-	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/getProfile", in, out, opts...)
-	if err != nil {
-		return nil, err
+func (m *ValidateTokenReply) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
 	}
-	return out, nil
+	return 0
 }
 
-// AuthServer is the server API for Auth service.
-type AuthServer interface {
-	AttemptLogin(context.Context, *LoginRequest) (*LoginReply, error)
-	RefreshToken(context.Context, *RefreshRequest) (*LoginReply, error)
-	GetProfile(context.Context, *ProfileRequest) (*Profile, error)
+func (m *ValidateTokenReply) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
 }
 
-// UnimplementedAuthServer can be embedded to have forward compatible implementations.
-type UnimplementedAuthServer struct {
+func (m *ValidateTokenReply) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
 }
 
-func (*UnimplementedAuthServer) AttemptLogin(ctx context.Context, req *LoginRequest) (*LoginReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method AttemptLogin not implemented")
-}
-func (*UnimplementedAuthServer) RefreshToken(ctx context.Context, req *RefreshRequest) (*LoginReply, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+type ValidateTokensRequest struct {
+	Tokens               []string `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
-func (*UnimplementedAuthServer) GetProfile(ctx context.Context, req *ProfileRequest) (*Profile, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+
+func (m *ValidateTokensRequest) Reset()         { *m = ValidateTokensRequest{} }
+func (m *ValidateTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokensRequest) ProtoMessage()    {}
+func (*ValidateTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{9}
 }
 
-func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
-	s.RegisterService(&_Auth_serviceDesc, srv)
+func (m *ValidateTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokensRequest.Unmarshal(m, b)
+}
+func (m *ValidateTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokensRequest.Merge(m, src)
+}
+func (m *ValidateTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokensRequest.Size(m)
+}
+func (m *ValidateTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokensRequest.DiscardUnknown(m)
 }
 
-func _Auth_AttemptLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(LoginRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).AttemptLogin(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/loginsrv_grpc.Auth/AttemptLogin",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).AttemptLogin(ctx, req.(*LoginRequest))
+var xxx_messageInfo_ValidateTokensRequest proto.InternalMessageInfo
+
+func (m *ValidateTokensRequest) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-func _Auth_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RefreshRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).RefreshToken(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/loginsrv_grpc.Auth/RefreshToken",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).RefreshToken(ctx, req.(*RefreshRequest))
-	}
-	return interceptor(ctx, in, info, handler)
+type ValidateTokensReply struct {
+	// one result per requested token, in the same order
+	Results              []*ValidateTokenReply `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}              `json:"-"`
+	XXX_unrecognized     []byte                `json:"-"`
+	XXX_sizecache        int32                 `json:"-"`
 }
 
-func _Auth_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ProfileRequest)
-	if err := dec(in); err != nil {
-		return nil, err
-	}
-	if interceptor == nil {
-		return srv.(AuthServer).GetProfile(ctx, in)
-	}
-	info := &grpc.UnaryServerInfo{
-		Server:     srv,
-		FullMethod: "/loginsrv_grpc.Auth/GetProfile",
-	}
-	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(AuthServer).GetProfile(ctx, req.(*ProfileRequest))
+func (m *ValidateTokensReply) Reset()         { *m = ValidateTokensReply{} }
+func (m *ValidateTokensReply) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokensReply) ProtoMessage()    {}
+func (*ValidateTokensReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{10}
+}
+
+func (m *ValidateTokensReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokensReply.Unmarshal(m, b)
+}
+func (m *ValidateTokensReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokensReply.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokensReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokensReply.Merge(m, src)
+}
+func (m *ValidateTokensReply) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokensReply.Size(m)
+}
+func (m *ValidateTokensReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokensReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTokensReply proto.InternalMessageInfo
+
+func (m *ValidateTokensReply) GetResults() []*ValidateTokenReply {
+	if m != nil {
+		return m.Results
 	}
-	return interceptor(ctx, in, info, handler)
+	return nil
 }
 
-var _Auth_serviceDesc = grpc.ServiceDesc{
-	ServiceName: "loginsrv_grpc.Auth",
-	HandlerType: (*AuthServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "attemptLogin",
-			Handler:    _Auth_AttemptLogin_Handler,
-		},
-		{
-			MethodName: "refreshToken",
-			Handler:    _Auth_RefreshToken_Handler,
+type StartOAuthLoginRequest struct {
+	// loginsrv oauth provider, e.g. github or google
+	Provider             string   `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartOAuthLoginRequest) Reset()         { *m = StartOAuthLoginRequest{} }
+func (m *StartOAuthLoginRequest) String() string { return proto.CompactTextString(m) }
+func (*StartOAuthLoginRequest) ProtoMessage()    {}
+func (*StartOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{11}
+}
+
+func (m *StartOAuthLoginRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartOAuthLoginRequest.Unmarshal(m, b)
+}
+func (m *StartOAuthLoginRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartOAuthLoginRequest.Marshal(b, m, deterministic)
+}
+func (m *StartOAuthLoginRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartOAuthLoginRequest.Merge(m, src)
+}
+func (m *StartOAuthLoginRequest) XXX_Size() int {
+	return xxx_messageInfo_StartOAuthLoginRequest.Size(m)
+}
+func (m *StartOAuthLoginRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartOAuthLoginRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartOAuthLoginRequest proto.InternalMessageInfo
+
+func (m *StartOAuthLoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+type StartOAuthLoginReply struct {
+	// the user agent is sent there to authorize the login, the client intercepts
+	// the redirect of the provider to the loginsrv callback to read the code
+	AuthorizationUrl string `protobuf:"bytes,1,opt,name=authorizationUrl,proto3" json:"authorizationUrl,omitempty"`
+	// must be passed back to completeOAuthLogin
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartOAuthLoginReply) Reset()         { *m = StartOAuthLoginReply{} }
+func (m *StartOAuthLoginReply) String() string { return proto.CompactTextString(m) }
+func (*StartOAuthLoginReply) ProtoMessage()    {}
+func (*StartOAuthLoginReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{12}
+}
+
+func (m *StartOAuthLoginReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartOAuthLoginReply.Unmarshal(m, b)
+}
+func (m *StartOAuthLoginReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartOAuthLoginReply.Marshal(b, m, deterministic)
+}
+func (m *StartOAuthLoginReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartOAuthLoginReply.Merge(m, src)
+}
+func (m *StartOAuthLoginReply) XXX_Size() int {
+	return xxx_messageInfo_StartOAuthLoginReply.Size(m)
+}
+func (m *StartOAuthLoginReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartOAuthLoginReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartOAuthLoginReply proto.InternalMessageInfo
+
+func (m *StartOAuthLoginReply) GetAuthorizationUrl() string {
+	if m != nil {
+		return m.AuthorizationUrl
+	}
+	return ""
+}
+
+func (m *StartOAuthLoginReply) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type CompleteOAuthLoginRequest struct {
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// code and state received by the provider callback
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompleteOAuthLoginRequest) Reset()         { *m = CompleteOAuthLoginRequest{} }
+func (m *CompleteOAuthLoginRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteOAuthLoginRequest) ProtoMessage()    {}
+func (*CompleteOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{13}
+}
+
+func (m *CompleteOAuthLoginRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Unmarshal(m, b)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteOAuthLoginRequest.Merge(m, src)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Size(m)
+}
+func (m *CompleteOAuthLoginRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteOAuthLoginRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteOAuthLoginRequest proto.InternalMessageInfo
+
+func (m *CompleteOAuthLoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *CompleteOAuthLoginRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *CompleteOAuthLoginRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type ListProvidersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProvidersRequest) Reset()         { *m = ListProvidersRequest{} }
+func (m *ListProvidersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListProvidersRequest) ProtoMessage()    {}
+func (*ListProvidersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{14}
+}
+
+func (m *ListProvidersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProvidersRequest.Unmarshal(m, b)
+}
+func (m *ListProvidersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProvidersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListProvidersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProvidersRequest.Merge(m, src)
+}
+func (m *ListProvidersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListProvidersRequest.Size(m)
+}
+func (m *ListProvidersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProvidersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProvidersRequest proto.InternalMessageInfo
+
+type ListProvidersReply struct {
+	Providers            []*LoginProvider `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListProvidersReply) Reset()         { *m = ListProvidersReply{} }
+func (m *ListProvidersReply) String() string { return proto.CompactTextString(m) }
+func (*ListProvidersReply) ProtoMessage()    {}
+func (*ListProvidersReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{15}
+}
+
+func (m *ListProvidersReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProvidersReply.Unmarshal(m, b)
+}
+func (m *ListProvidersReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProvidersReply.Marshal(b, m, deterministic)
+}
+func (m *ListProvidersReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProvidersReply.Merge(m, src)
+}
+func (m *ListProvidersReply) XXX_Size() int {
+	return xxx_messageInfo_ListProvidersReply.Size(m)
+}
+func (m *ListProvidersReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProvidersReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProvidersReply proto.InternalMessageInfo
+
+func (m *ListProvidersReply) GetProviders() []*LoginProvider {
+	if m != nil {
+		return m.Providers
+	}
+	return nil
+}
+
+type LoginProvider struct {
+	Name                 string             `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName          string             `protobuf:"bytes,2,opt,name=displayName,proto3" json:"displayName,omitempty"`
+	Flow                 LoginProvider_Flow `protobuf:"varint,3,opt,name=flow,proto3,enum=loginsrv_grpc.LoginProvider_Flow" json:"flow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
+	XXX_unrecognized     []byte             `json:"-"`
+	XXX_sizecache        int32              `json:"-"`
+}
+
+func (m *LoginProvider) Reset()         { *m = LoginProvider{} }
+func (m *LoginProvider) String() string { return proto.CompactTextString(m) }
+func (*LoginProvider) ProtoMessage()    {}
+func (*LoginProvider) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{16}
+}
+
+func (m *LoginProvider) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginProvider.Unmarshal(m, b)
+}
+func (m *LoginProvider) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginProvider.Marshal(b, m, deterministic)
+}
+func (m *LoginProvider) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginProvider.Merge(m, src)
+}
+func (m *LoginProvider) XXX_Size() int {
+	return xxx_messageInfo_LoginProvider.Size(m)
+}
+func (m *LoginProvider) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginProvider.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginProvider proto.InternalMessageInfo
+
+func (m *LoginProvider) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LoginProvider) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *LoginProvider) GetFlow() LoginProvider_Flow {
+	if m != nil {
+		return m.Flow
+	}
+	return LoginProvider_PASSWORD
+}
+
+type WatchSessionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchSessionRequest) Reset()         { *m = WatchSessionRequest{} }
+func (m *WatchSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchSessionRequest) ProtoMessage()    {}
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{17}
+}
+
+func (m *WatchSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchSessionRequest.Unmarshal(m, b)
+}
+func (m *WatchSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchSessionRequest.Merge(m, src)
+}
+func (m *WatchSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchSessionRequest.Size(m)
+}
+func (m *WatchSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchSessionRequest proto.InternalMessageInfo
+
+type SessionEvent struct {
+	Type                 SessionEvent_Type `protobuf:"varint,1,opt,name=type,proto3,enum=loginsrv_grpc.SessionEvent_Type" json:"type,omitempty"`
+	AccessToken          string            `protobuf:"bytes,2,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	ExpiresAt            int64             `protobuf:"varint,3,opt,name=expiresAt,proto3" json:"expiresAt,omitempty"`
+	Message              string            `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *SessionEvent) Reset()         { *m = SessionEvent{} }
+func (m *SessionEvent) String() string { return proto.CompactTextString(m) }
+func (*SessionEvent) ProtoMessage()    {}
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{18}
+}
+
+func (m *SessionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionEvent.Unmarshal(m, b)
+}
+func (m *SessionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionEvent.Marshal(b, m, deterministic)
+}
+func (m *SessionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionEvent.Merge(m, src)
+}
+func (m *SessionEvent) XXX_Size() int {
+	return xxx_messageInfo_SessionEvent.Size(m)
+}
+func (m *SessionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionEvent proto.InternalMessageInfo
+
+func (m *SessionEvent) GetType() SessionEvent_Type {
+	if m != nil {
+		return m.Type
+	}
+	return SessionEvent_TOKEN_REFRESHED
+}
+
+func (m *SessionEvent) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type ExchangeTokenRequest struct {
+	// token of the user the caller acts for, issued by loginsrv or by a previous exchange
+	SubjectToken string `protobuf:"bytes,1,opt,name=subjectToken,proto3" json:"subjectToken,omitempty"`
+	// service the new token is meant for
+	Audience string `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"`
+	// scopes of the new token, within the scopes of the subject token when it has some
+	Scopes               []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExchangeTokenRequest) Reset()         { *m = ExchangeTokenRequest{} }
+func (m *ExchangeTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*ExchangeTokenRequest) ProtoMessage()    {}
+func (*ExchangeTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{19}
+}
+
+func (m *ExchangeTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExchangeTokenRequest.Unmarshal(m, b)
+}
+func (m *ExchangeTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExchangeTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *ExchangeTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExchangeTokenRequest.Merge(m, src)
+}
+func (m *ExchangeTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_ExchangeTokenRequest.Size(m)
+}
+func (m *ExchangeTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExchangeTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExchangeTokenRequest proto.InternalMessageInfo
+
+func (m *ExchangeTokenRequest) GetSubjectToken() string {
+	if m != nil {
+		return m.SubjectToken
+	}
+	return ""
+}
+
+func (m *ExchangeTokenRequest) GetAudience() string {
+	if m != nil {
+		return m.Audience
+	}
+	return ""
+}
+
+func (m *ExchangeTokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type ExchangeTokenReply struct {
+	AccessToken string `protobuf:"bytes,1,opt,name=accessToken,proto3" json:"accessToken,omitempty"`
+	// always urn:ietf:params:oauth:token-type:jwt
+	IssuedTokenType string `protobuf:"bytes,2,opt,name=issuedTokenType,proto3" json:"issuedTokenType,omitempty"`
+	// always bearer
+	TokenType string `protobuf:"bytes,3,opt,name=tokenType,proto3" json:"tokenType,omitempty"`
+	// seconds left before the token expires
+	ExpiresIn            int64    `protobuf:"varint,4,opt,name=expiresIn,proto3" json:"expiresIn,omitempty"`
+	Scopes               []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExchangeTokenReply) Reset()         { *m = ExchangeTokenReply{} }
+func (m *ExchangeTokenReply) String() string { return proto.CompactTextString(m) }
+func (*ExchangeTokenReply) ProtoMessage()    {}
+func (*ExchangeTokenReply) Descriptor() ([]byte, []int) {
+	return fileDescriptor_ba74aec577d9b91b, []int{20}
+}
+
+func (m *ExchangeTokenReply) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExchangeTokenReply.Unmarshal(m, b)
+}
+func (m *ExchangeTokenReply) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExchangeTokenReply.Marshal(b, m, deterministic)
+}
+func (m *ExchangeTokenReply) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExchangeTokenReply.Merge(m, src)
+}
+func (m *ExchangeTokenReply) XXX_Size() int {
+	return xxx_messageInfo_ExchangeTokenReply.Size(m)
+}
+func (m *ExchangeTokenReply) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExchangeTokenReply.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExchangeTokenReply proto.InternalMessageInfo
+
+func (m *ExchangeTokenReply) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+func (m *ExchangeTokenReply) GetIssuedTokenType() string {
+	if m != nil {
+		return m.IssuedTokenType
+	}
+	return ""
+}
+
+func (m *ExchangeTokenReply) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
+	}
+	return ""
+}
+
+func (m *ExchangeTokenReply) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *ExchangeTokenReply) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("loginsrv_grpc.LoginProvider_Flow", LoginProvider_Flow_name, LoginProvider_Flow_value)
+	proto.RegisterEnum("loginsrv_grpc.SessionEvent_Type", SessionEvent_Type_name, SessionEvent_Type_value)
+	proto.RegisterType((*LoginRequest)(nil), "loginsrv_grpc.LoginRequest")
+	proto.RegisterMapType((map[string]string)(nil), "loginsrv_grpc.LoginRequest.ExtraEntry")
+	proto.RegisterType((*RefreshRequest)(nil), "loginsrv_grpc.RefreshRequest")
+	proto.RegisterType((*LoginReply)(nil), "loginsrv_grpc.LoginReply")
+	proto.RegisterType((*LogoutRequest)(nil), "loginsrv_grpc.LogoutRequest")
+	proto.RegisterType((*LogoutReply)(nil), "loginsrv_grpc.LogoutReply")
+	proto.RegisterType((*ProfileRequest)(nil), "loginsrv_grpc.ProfileRequest")
+	proto.RegisterType((*Profile)(nil), "loginsrv_grpc.Profile")
+	proto.RegisterType((*ValidateTokenRequest)(nil), "loginsrv_grpc.ValidateTokenRequest")
+	proto.RegisterType((*ValidateTokenReply)(nil), "loginsrv_grpc.ValidateTokenReply")
+	proto.RegisterType((*ValidateTokensRequest)(nil), "loginsrv_grpc.ValidateTokensRequest")
+	proto.RegisterType((*ValidateTokensReply)(nil), "loginsrv_grpc.ValidateTokensReply")
+	proto.RegisterType((*StartOAuthLoginRequest)(nil), "loginsrv_grpc.StartOAuthLoginRequest")
+	proto.RegisterType((*StartOAuthLoginReply)(nil), "loginsrv_grpc.StartOAuthLoginReply")
+	proto.RegisterType((*CompleteOAuthLoginRequest)(nil), "loginsrv_grpc.CompleteOAuthLoginRequest")
+	proto.RegisterType((*ListProvidersRequest)(nil), "loginsrv_grpc.ListProvidersRequest")
+	proto.RegisterType((*ListProvidersReply)(nil), "loginsrv_grpc.ListProvidersReply")
+	proto.RegisterType((*LoginProvider)(nil), "loginsrv_grpc.LoginProvider")
+	proto.RegisterType((*WatchSessionRequest)(nil), "loginsrv_grpc.WatchSessionRequest")
+	proto.RegisterType((*SessionEvent)(nil), "loginsrv_grpc.SessionEvent")
+	proto.RegisterType((*ExchangeTokenRequest)(nil), "loginsrv_grpc.ExchangeTokenRequest")
+	proto.RegisterType((*ExchangeTokenReply)(nil), "loginsrv_grpc.ExchangeTokenReply")
+}
+
+func init() { proto.RegisterFile("loginsrv.proto", fileDescriptor_ba74aec577d9b91b) }
+
+var fileDescriptor_ba74aec577d9b91b = []byte{
+	// 1171 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xdd, 0x6e, 0xdb, 0x36,
+	0x14, 0x8e, 0x62, 0x27, 0x4e, 0x4e, 0xec, 0xc4, 0x63, 0x5d, 0x43, 0x75, 0x5b, 0xc0, 0x65, 0xb7,
+	0x21, 0x18, 0x06, 0xaf, 0xc8, 0x3a, 0xa0, 0xe8, 0x76, 0x13, 0xd4, 0x6a, 0x9b, 0x35, 0x88, 0x0d,
+	0xda, 0xfd, 0xc3, 0x0a, 0x04, 0x8a, 0xcc, 0x3a, 0x5a, 0x65, 0x49, 0x23, 0x29, 0xa7, 0xde, 0xd3,
+	0xec, 0x7a, 0xbb, 0xdd, 0x63, 0xec, 0x09, 0x06, 0xec, 0x4d, 0x76, 0x31, 0x90, 0x14, 0x63, 0x49,
+	0x76, 0x9c, 0xee, 0x4e, 0xdf, 0xe1, 0xe1, 0xf9, 0xf9, 0x48, 0x7e, 0xc7, 0x86, 0xdd, 0x20, 0x1a,
+	0xfb, 0x21, 0x67, 0xd3, 0x4e, 0xcc, 0x22, 0x11, 0xa1, 0x9a, 0xc1, 0xa7, 0x63, 0x16, 0x7b, 0xf8,
+	0x6f, 0x0b, 0xaa, 0xc7, 0xd2, 0x42, 0xe8, 0x2f, 0x09, 0xe5, 0x02, 0xb5, 0x60, 0x2b, 0xe1, 0x94,
+	0x85, 0xee, 0x84, 0xda, 0x56, 0xdb, 0xda, 0xdf, 0x26, 0x97, 0x58, 0xae, 0xc5, 0x2e, 0xe7, 0x17,
+	0x11, 0x1b, 0xd9, 0xeb, 0x7a, 0xcd, 0x60, 0xb5, 0xc6, 0xa2, 0xa9, 0x3f, 0xa2, 0xcc, 0x2e, 0xa5,
+	0x6b, 0x29, 0x46, 0x3f, 0xc0, 0x06, 0xfd, 0x28, 0x98, 0x6b, 0x97, 0xdb, 0xa5, 0xfd, 0x9d, 0x83,
+	0x2f, 0x3b, 0xb9, 0x1a, 0x3a, 0xd9, 0xfc, 0x1d, 0x47, 0x3a, 0x3a, 0xa1, 0x60, 0x33, 0xa2, 0x37,
+	0xb5, 0x1e, 0x01, 0xcc, 0x8d, 0xa8, 0x0e, 0xa5, 0x0f, 0x74, 0x96, 0x96, 0x26, 0x3f, 0x51, 0x03,
+	0x36, 0xa6, 0x6e, 0x90, 0xd0, 0xb4, 0x24, 0x0d, 0x1e, 0xaf, 0x3f, 0xb2, 0x70, 0x1d, 0x76, 0x09,
+	0x7d, 0xcf, 0x28, 0x3f, 0x4f, 0xa3, 0xe3, 0xbf, 0x2c, 0x80, 0x34, 0x5d, 0x1c, 0xcc, 0x50, 0x1b,
+	0x76, 0x5c, 0xcf, 0xa3, 0x9c, 0x0f, 0xa3, 0x0f, 0x34, 0x4c, 0x83, 0x66, 0x4d, 0xe8, 0x0e, 0x6c,
+	0xd3, 0x8f, 0xb1, 0xcf, 0x28, 0x3f, 0x14, 0x2a, 0x41, 0x89, 0xcc, 0x0d, 0xa8, 0x03, 0x88, 0xe9,
+	0x04, 0x94, 0x13, 0x3a, 0x71, 0xfd, 0xd0, 0x0f, 0xc7, 0xaa, 0xfd, 0x0d, 0xb2, 0x64, 0x45, 0x46,
+	0x13, 0x32, 0xec, 0x70, 0x16, 0x53, 0xbb, 0xac, 0xb2, 0xcd, 0x0d, 0xe8, 0x01, 0x54, 0x62, 0x16,
+	0xbd, 0xf7, 0x03, 0x6a, 0x6f, 0xb4, 0xad, 0xfd, 0x9d, 0x83, 0x66, 0x81, 0xa8, 0xbe, 0x5e, 0x25,
+	0xc6, 0x0d, 0xef, 0x41, 0xed, 0x38, 0x1a, 0x47, 0x89, 0x30, 0xfd, 0xd5, 0x60, 0xc7, 0x18, 0xe2,
+	0x60, 0x26, 0x09, 0x30, 0x7b, 0x52, 0x87, 0x7f, 0x2c, 0xa8, 0xa4, 0x26, 0x49, 0xe5, 0x20, 0x39,
+	0x33, 0x54, 0x0e, 0x92, 0x33, 0x64, 0x43, 0xa5, 0xef, 0x7b, 0x22, 0x61, 0x86, 0x4c, 0x03, 0x11,
+	0x82, 0xf2, 0x89, 0xbc, 0x12, 0xfa, 0x68, 0xd5, 0xb7, 0x24, 0xde, 0x99, 0xb8, 0x7e, 0x90, 0x76,
+	0xa2, 0x01, 0x6a, 0xc2, 0x66, 0x8f, 0xf9, 0x63, 0x3f, 0x54, 0x4d, 0x6c, 0x93, 0x14, 0x49, 0xbb,
+	0x23, 0x89, 0x9b, 0xd9, 0x9b, 0x8a, 0xc6, 0x14, 0x49, 0x4e, 0x88, 0x61, 0xca, 0xae, 0x28, 0xea,
+	0xe6, 0x06, 0xb9, 0xab, 0x1b, 0x49, 0xfa, 0xec, 0x2d, 0x1d, 0x4d, 0x23, 0x69, 0x7f, 0xc6, 0xa2,
+	0x24, 0xe6, 0xf6, 0x76, 0xbb, 0x24, 0xed, 0x1a, 0xe1, 0xaf, 0xa1, 0xf1, 0xca, 0x0d, 0xfc, 0x91,
+	0x2b, 0xa8, 0x3a, 0x40, 0x73, 0xad, 0x1b, 0xb0, 0x21, 0x32, 0x67, 0xac, 0x01, 0xfe, 0xdd, 0x02,
+	0x54, 0x70, 0x97, 0xd7, 0xa2, 0x09, 0x9b, 0xae, 0x27, 0xfc, 0xa9, 0x7e, 0x01, 0x5b, 0x24, 0x45,
+	0xd9, 0x03, 0x5a, 0xff, 0xa4, 0x03, 0xca, 0x5c, 0x9f, 0xa3, 0xd0, 0x2e, 0xe5, 0xae, 0xcf, 0x91,
+	0x6a, 0x82, 0x51, 0x97, 0x47, 0x61, 0xca, 0x60, 0x8a, 0x64, 0xb1, 0x94, 0xb1, 0x88, 0xa5, 0x0c,
+	0x6a, 0x80, 0xbf, 0x81, 0x9b, 0xb9, 0x5a, 0xb9, 0xe9, 0xad, 0x09, 0x9b, 0xaa, 0x1d, 0x6e, 0x5b,
+	0x9a, 0x0b, 0x8d, 0x30, 0x81, 0x1b, 0xc5, 0x0d, 0xb2, 0xbb, 0xef, 0xa1, 0xc2, 0x28, 0x4f, 0x02,
+	0xa1, 0xfd, 0x77, 0x0e, 0xee, 0x15, 0xba, 0x58, 0x64, 0x84, 0x98, 0x1d, 0xf8, 0x21, 0x34, 0x07,
+	0xc2, 0x65, 0xa2, 0x77, 0x98, 0x88, 0xf3, 0xa2, 0x70, 0x5c, 0x0a, 0x80, 0x95, 0x17, 0x00, 0xfc,
+	0x06, 0x1a, 0x0b, 0xbb, 0x64, 0x29, 0x5f, 0x41, 0xdd, 0x4d, 0xc4, 0x79, 0xc4, 0xfc, 0x5f, 0x5d,
+	0xe1, 0x47, 0xe1, 0x4b, 0x16, 0xa4, 0x7b, 0x17, 0xec, 0x92, 0x14, 0x2e, 0x5c, 0x71, 0xf9, 0xcc,
+	0x15, 0xc0, 0x2e, 0xdc, 0x7a, 0x12, 0x4d, 0xe2, 0x80, 0x0a, 0xfa, 0xbf, 0x4a, 0x92, 0x17, 0xda,
+	0x8b, 0x46, 0x26, 0x9a, 0xfa, 0x9e, 0xa7, 0x28, 0x65, 0x53, 0x34, 0xa1, 0x71, 0xec, 0x73, 0xd1,
+	0x4f, 0x77, 0x1a, 0xda, 0x71, 0x1f, 0x50, 0xc1, 0x2e, 0x5b, 0x7a, 0x0c, 0xdb, 0x26, 0x87, 0xe1,
+	0xf7, 0xce, 0x32, 0xbd, 0x33, 0xdb, 0xc8, 0xdc, 0x1d, 0xff, 0x66, 0x41, 0x2d, 0xb7, 0x28, 0xab,
+	0xcc, 0x28, 0xb1, 0xfa, 0x96, 0xa2, 0x35, 0xf2, 0x79, 0x1c, 0xb8, 0x33, 0xf5, 0x22, 0x75, 0x03,
+	0x59, 0x13, 0xfa, 0x0e, 0xca, 0xef, 0x83, 0xe8, 0x42, 0xb5, 0xb1, 0xbb, 0x70, 0xbc, 0xb9, 0x0c,
+	0x9d, 0xa7, 0x41, 0x74, 0x41, 0x94, 0x3b, 0xc6, 0x50, 0x96, 0x08, 0x55, 0x61, 0xab, 0x7f, 0x38,
+	0x18, 0xbc, 0xee, 0x91, 0x6e, 0x7d, 0x4d, 0x22, 0xe2, 0x74, 0x8f, 0x88, 0xf3, 0x64, 0x58, 0xb7,
+	0xf0, 0x4d, 0xb8, 0xf1, 0xda, 0x15, 0xde, 0xf9, 0x80, 0x72, 0xee, 0x47, 0x86, 0x69, 0xfc, 0xaf,
+	0x05, 0xd5, 0xd4, 0xe4, 0x4c, 0x69, 0x28, 0xd0, 0x43, 0x28, 0x8b, 0x59, 0xac, 0x0b, 0xdf, 0x3d,
+	0x68, 0x17, 0x4a, 0xc8, 0xba, 0x76, 0xa4, 0xf6, 0x11, 0xe5, 0x5d, 0xd4, 0xe3, 0xf5, 0x6b, 0xf4,
+	0xb8, 0x54, 0xd4, 0x63, 0x1b, 0x2a, 0x13, 0xca, 0xb9, 0x3b, 0x36, 0xea, 0x6a, 0x20, 0x7e, 0x07,
+	0x65, 0xa5, 0xb1, 0x37, 0x60, 0x6f, 0xd8, 0x7b, 0xe1, 0x9c, 0x9c, 0x12, 0xe7, 0x29, 0x71, 0x06,
+	0xcf, 0x1d, 0xd9, 0x22, 0x82, 0x5d, 0xe7, 0x4d, 0xff, 0x88, 0xbc, 0x3d, 0x7d, 0x7d, 0x48, 0x4e,
+	0x8e, 0x4e, 0x9e, 0xd5, 0x2d, 0xb4, 0x03, 0x15, 0x65, 0x73, 0xba, 0xf5, 0x75, 0x09, 0x88, 0xf3,
+	0xaa, 0xf7, 0xc2, 0xe9, 0xd6, 0x4b, 0xe8, 0x33, 0xa8, 0x3d, 0xed, 0x91, 0x27, 0x4e, 0xf7, 0xf4,
+	0xb8, 0xf7, 0xac, 0xf7, 0x72, 0x58, 0x2f, 0xe3, 0x10, 0x1a, 0xce, 0x47, 0xef, 0xdc, 0x0d, 0xc7,
+	0x79, 0xd5, 0xc1, 0x50, 0xe5, 0xc9, 0xd9, 0xcf, 0xd4, 0x13, 0xd9, 0x01, 0x93, 0xb3, 0xc9, 0x4b,
+	0xea, 0x26, 0x23, 0x9f, 0x86, 0x9e, 0x39, 0xcb, 0x4b, 0x2c, 0x5f, 0x36, 0xf7, 0xa2, 0x98, 0x72,
+	0xbb, 0xa4, 0x5f, 0xb6, 0x46, 0xf8, 0x4f, 0x0b, 0x50, 0x21, 0xe1, 0xa7, 0x8d, 0xb3, 0x7d, 0xd8,
+	0xf3, 0x39, 0x4f, 0xe8, 0x68, 0x78, 0x39, 0x86, 0x74, 0xce, 0xa2, 0x39, 0x3f, 0xaa, 0x4a, 0xc5,
+	0x51, 0x95, 0xd3, 0xb5, 0xf2, 0x12, 0x5d, 0x4b, 0xcb, 0xde, 0xc8, 0x96, 0x7d, 0xf0, 0x47, 0x05,
+	0xca, 0xf2, 0x91, 0xa2, 0xe7, 0x50, 0x75, 0x85, 0xa0, 0x93, 0x58, 0xa8, 0xcb, 0x88, 0x6e, 0xaf,
+	0xf8, 0x45, 0xd0, 0xba, 0xb5, 0x7c, 0x51, 0xce, 0xb7, 0x35, 0xf4, 0x23, 0x54, 0xd3, 0x39, 0xab,
+	0x1b, 0xbc, 0x5b, 0x70, 0xce, 0xcf, 0xff, 0xd5, 0xb1, 0x1c, 0x80, 0x31, 0x15, 0x66, 0x3a, 0xde,
+	0xbd, 0x42, 0xdb, 0xd3, 0x48, 0x57, 0x48, 0x3f, 0x5e, 0x43, 0x5d, 0xd8, 0x0c, 0xd4, 0x0c, 0x46,
+	0x4b, 0x1e, 0xfe, 0x7c, 0x56, 0xb7, 0x5a, 0x57, 0xac, 0xea, 0x62, 0xde, 0x42, 0x6d, 0x9a, 0xd5,
+	0x61, 0x74, 0x7f, 0xb5, 0x4a, 0xeb, 0x98, 0xd7, 0x4b, 0x39, 0x5e, 0x43, 0xef, 0x60, 0x37, 0x17,
+	0x9a, 0xa3, 0xcf, 0x57, 0x6d, 0x33, 0x82, 0xd7, 0xc2, 0xd7, 0x78, 0xe9, 0xe8, 0xa7, 0xb0, 0xc7,
+	0xf3, 0x5a, 0x8f, 0xbe, 0x28, 0x3e, 0xff, 0xa5, 0x13, 0xa4, 0x75, 0xff, 0x3a, 0x37, 0x9d, 0xe0,
+	0x27, 0x40, 0xde, 0x82, 0xe4, 0xa3, 0xfd, 0xc2, 0xe6, 0x2b, 0xa7, 0xc2, 0xea, 0x3b, 0xf0, 0x16,
+	0x6a, 0x41, 0x56, 0xd4, 0x17, 0x68, 0x5f, 0x36, 0x0a, 0x5a, 0xf7, 0x56, 0x3b, 0xe9, 0xd0, 0x03,
+	0xa8, 0x5e, 0x64, 0xa4, 0x13, 0x15, 0xe9, 0x5c, 0xa2, 0xab, 0xad, 0xdb, 0x2b, 0x84, 0x13, 0xaf,
+	0x3d, 0xb0, 0x64, 0xbd, 0x34, 0x2b, 0x04, 0x0b, 0xf5, 0x2e, 0xd3, 0xa5, 0xd6, 0xbd, 0xd5, 0x4e,
+	0xaa, 0xde, 0xb3, 0x4d, 0xf5, 0x87, 0xe1, 0xdb, 0xff, 0x06, 0x00, 0x83, 0x69, 0x3e, 0xea, 0x42,
+	0x0c, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthClient is the client API for Auth service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthClient interface {
+	AttemptLogin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error)
+	RefreshToken(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginReply, error)
+	GetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutReply, error)
+	ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenReply, error)
+	ValidateTokens(ctx context.Context, in *ValidateTokensRequest, opts ...grpc.CallOption) (*ValidateTokensReply, error)
+	StartOAuthLogin(ctx context.Context, in *StartOAuthLoginRequest, opts ...grpc.CallOption) (*StartOAuthLoginReply, error)
+	CompleteOAuthLogin(ctx context.Context, in *CompleteOAuthLoginRequest, opts ...grpc.CallOption) (*LoginReply, error)
+	ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersReply, error)
+	WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (Auth_WatchSessionClient, error)
+	ExchangeToken(ctx context.Context, in *ExchangeTokenRequest, opts ...grpc.CallOption) (*ExchangeTokenReply, error)
+}
+
+type authClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthClient(cc *grpc.ClientConn) AuthClient {
+	return &authClient{cc}
+}
+
+func (c *authClient) AttemptLogin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/attemptLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) RefreshToken(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/refreshToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) GetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/getProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutReply, error) {
+	out := new(LogoutReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenReply, error) {
+	out := new(ValidateTokenReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/validateToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ValidateTokens(ctx context.Context, in *ValidateTokensRequest, opts ...grpc.CallOption) (*ValidateTokensReply, error) {
+	out := new(ValidateTokensReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/validateTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) StartOAuthLogin(ctx context.Context, in *StartOAuthLoginRequest, opts ...grpc.CallOption) (*StartOAuthLoginReply, error) {
+	out := new(StartOAuthLoginReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/startOAuthLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) CompleteOAuthLogin(ctx context.Context, in *CompleteOAuthLoginRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	out := new(LoginReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/completeOAuthLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersReply, error) {
+	out := new(ListProvidersReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/listProviders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authClient) WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (Auth_WatchSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Auth_serviceDesc.Streams[0], "/loginsrv_grpc.Auth/watchSession", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authWatchSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Auth_WatchSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type authWatchSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *authWatchSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authClient) ExchangeToken(ctx context.Context, in *ExchangeTokenRequest, opts ...grpc.CallOption) (*ExchangeTokenReply, error) {
+	out := new(ExchangeTokenReply)
+	err := c.cc.Invoke(ctx, "/loginsrv_grpc.Auth/exchangeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServer is the server API for Auth service.
+type AuthServer interface {
+	AttemptLogin(context.Context, *LoginRequest) (*LoginReply, error)
+	RefreshToken(context.Context, *RefreshRequest) (*LoginReply, error)
+	GetProfile(context.Context, *ProfileRequest) (*Profile, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutReply, error)
+	ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenReply, error)
+	ValidateTokens(context.Context, *ValidateTokensRequest) (*ValidateTokensReply, error)
+	StartOAuthLogin(context.Context, *StartOAuthLoginRequest) (*StartOAuthLoginReply, error)
+	CompleteOAuthLogin(context.Context, *CompleteOAuthLoginRequest) (*LoginReply, error)
+	ListProviders(context.Context, *ListProvidersRequest) (*ListProvidersReply, error)
+	WatchSession(*WatchSessionRequest, Auth_WatchSessionServer) error
+	ExchangeToken(context.Context, *ExchangeTokenRequest) (*ExchangeTokenReply, error)
+}
+
+// UnimplementedAuthServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthServer struct {
+}
+
+func (*UnimplementedAuthServer) AttemptLogin(ctx context.Context, req *LoginRequest) (*LoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttemptLogin not implemented")
+}
+func (*UnimplementedAuthServer) RefreshToken(ctx context.Context, req *RefreshRequest) (*LoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (*UnimplementedAuthServer) GetProfile(ctx context.Context, req *ProfileRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (*UnimplementedAuthServer) Logout(ctx context.Context, req *LogoutRequest) (*LogoutReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedAuthServer) ValidateToken(ctx context.Context, req *ValidateTokenRequest) (*ValidateTokenReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateToken not implemented")
+}
+func (*UnimplementedAuthServer) ValidateTokens(ctx context.Context, req *ValidateTokensRequest) (*ValidateTokensReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateTokens not implemented")
+}
+func (*UnimplementedAuthServer) StartOAuthLogin(ctx context.Context, req *StartOAuthLoginRequest) (*StartOAuthLoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartOAuthLogin not implemented")
+}
+func (*UnimplementedAuthServer) CompleteOAuthLogin(ctx context.Context, req *CompleteOAuthLoginRequest) (*LoginReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteOAuthLogin not implemented")
+}
+func (*UnimplementedAuthServer) ListProviders(ctx context.Context, req *ListProvidersRequest) (*ListProvidersReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProviders not implemented")
+}
+func (*UnimplementedAuthServer) WatchSession(req *WatchSessionRequest, srv Auth_WatchSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSession not implemented")
+}
+func (*UnimplementedAuthServer) ExchangeToken(ctx context.Context, req *ExchangeTokenRequest) (*ExchangeTokenReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExchangeToken not implemented")
+}
+
+func RegisterAuthServer(s *grpc.Server, srv AuthServer) {
+	s.RegisterService(&_Auth_serviceDesc, srv)
+}
+
+func _Auth_AttemptLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).AttemptLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/AttemptLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).AttemptLogin(ctx, req.(*LoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/RefreshToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).RefreshToken(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).GetProfile(ctx, req.(*ProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ValidateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ValidateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/ValidateToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ValidateToken(ctx, req.(*ValidateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ValidateTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ValidateTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/ValidateTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ValidateTokens(ctx, req.(*ValidateTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_StartOAuthLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartOAuthLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).StartOAuthLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/StartOAuthLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).StartOAuthLogin(ctx, req.(*StartOAuthLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_CompleteOAuthLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteOAuthLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).CompleteOAuthLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/CompleteOAuthLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).CompleteOAuthLogin(ctx, req.(*CompleteOAuthLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_ListProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvidersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ListProviders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/ListProviders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ListProviders(ctx, req.(*ListProvidersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Auth_WatchSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServer).WatchSession(m, &authWatchSessionServer{stream})
+}
+
+type Auth_WatchSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type authWatchSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *authWatchSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Auth_ExchangeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServer).ExchangeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv_grpc.Auth/ExchangeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServer).ExchangeToken(ctx, req.(*ExchangeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Auth_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "loginsrv_grpc.Auth",
+	HandlerType: (*AuthServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "attemptLogin",
+			Handler:    _Auth_AttemptLogin_Handler,
+		},
+		{
+			MethodName: "refreshToken",
+			Handler:    _Auth_RefreshToken_Handler,
 		},
 		{
 			MethodName: "getProfile",
 			Handler:    _Auth_GetProfile_Handler,
 		},
+		{
+			MethodName: "logout",
+			Handler:    _Auth_Logout_Handler,
+		},
+		{
+			MethodName: "validateToken",
+			Handler:    _Auth_ValidateToken_Handler,
+		},
+		{
+			MethodName: "validateTokens",
+			Handler:    _Auth_ValidateTokens_Handler,
+		},
+		{
+			MethodName: "startOAuthLogin",
+			Handler:    _Auth_StartOAuthLogin_Handler,
+		},
+		{
+			MethodName: "completeOAuthLogin",
+			Handler:    _Auth_CompleteOAuthLogin_Handler,
+		},
+		{
+			MethodName: "listProviders",
+			Handler:    _Auth_ListProviders_Handler,
+		},
+		{
+			MethodName: "exchangeToken",
+			Handler:    _Auth_ExchangeToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "watchSession",
+			Handler:       _Auth_WatchSession_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "loginsrv.proto",
 }
@@ -0,0 +1,52 @@
+package loginsrv_grpc
+
+import (
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Middleware validates the token of http requests like Authenticate does for RPCs,
+// it is read from the Authorization header or the jwt_token cookie, renamed by WithCookieToken,
+// and the request path is the authorizer resource,
+// handlers get the profile through ProfileFromContext
+func (s *LoginSrvServer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := requestToken(r, s.httpCookieName())
+		if token == "" {
+			writeHTTPError(w, status.Error(codes.Unauthenticated, "missing token"))
+			return
+		}
+
+		profile, err := s.authorize(r.Context(), token, r.URL.Path)
+		if err != nil {
+			writeHTTPError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(withProfile(r.Context(), profile)))
+	})
+}
+
+// requestToken returns the bearer token of r, or the value of the cookie when it has none
+func requestToken(r *http.Request, cookieName string) string {
+	if token := bearerToken(r); token != "" {
+		return token
+	}
+	if cookieName == "" {
+		return ""
+	}
+	if cookie, err := r.Cookie(cookieName); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// writeHTTPError answers with the http status of a grpc error
+func writeHTTPError(w http.ResponseWriter, err error) {
+	s := status.Convert(err)
+	if s.Code() == codes.Unauthenticated {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	http.Error(w, s.Message(), httpStatusFromCode(s.Code()))
+}
@@ -0,0 +1,63 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithAuthorizer(func(ctx context.Context, profile *Profile, resource string) error {
+		if strings.HasPrefix(resource, "/admin") {
+			return RequireGroups("admin")(ctx, profile, resource)
+		}
+		return nil
+	}))
+	token := obtainTokenOrFail(t, srv)
+
+	h := srv.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		profile, ok := ProfileFromContext(r.Context())
+		if !ok {
+			t.Error("Profile should be in the request context")
+			return
+		}
+		w.Write([]byte(profile.Sub))
+	}))
+
+	serve := func(path string, setup func(*http.Request)) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", path, nil)
+		setup(req)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w
+	}
+
+	w := serve("/orders", func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) })
+	if w.Code != http.StatusOK || w.Body.String() != "bob" {
+		t.Errorf("Bearer token should pass but got %d %s", w.Code, w.Body)
+	}
+
+	w = serve("/orders", func(r *http.Request) { r.AddCookie(&http.Cookie{Name: jwtCookieName, Value: token}) })
+	if w.Code != http.StatusOK || w.Body.String() != "bob" {
+		t.Errorf("Cookie token should pass but got %d %s", w.Code, w.Body)
+	}
+
+	w = serve("/orders", func(r *http.Request) {})
+	if w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Errorf("Missing token should be challenged but got %d %v", w.Code, w.Header())
+	}
+
+	w = serve("/orders", func(r *http.Request) { r.Header.Set("Authorization", "Bearer garbage") })
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Invalid token should be rejected but got %d", w.Code)
+	}
+
+	w = serve("/admin/users", func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) })
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Authorizer should deny the path but got %d", w.Code)
+	}
+}
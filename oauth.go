@@ -0,0 +1,107 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// oauthStateCookieName is the cookie loginsrv keeps the oauth state in
+	oauthStateCookieName = "oauthState"
+	// oauthStateLifetime bounds the time a user has to authorize on the provider
+	oauthStateLifetime = 10 * time.Minute
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// StartOAuthLogin asks loginsrv to start the oauth flow of a provider
+// the client sends the user agent to the returned url and keeps the state for CompleteOAuthLogin,
+// the provider redirects back to the /login/<provider> callback of loginsrv
+// which must be intercepted by the client, e.g. in a webview, to pass its code and state to CompleteOAuthLogin:
+// loginsrv set its state cookie on this server and not on the user agent, so a followed redirect fails with 403
+func (s *LoginSrvServer) StartOAuthLogin(ctx context.Context, request *StartOAuthLoginRequest) (*StartOAuthLoginReply, error) {
+	if !providerNamePattern.MatchString(request.Provider) {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid provider")
+	}
+
+	req, err := http.NewRequest("GET", *s.baseURL+"/login/"+request.Provider, nil)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+
+	resp, err := s.noRedirectClient().Do(req)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, grpc.Errorf(codes.NotFound, "unknown provider")
+	}
+	location, err := resp.Location()
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+
+	state := location.Query().Get("state")
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == oauthStateCookieName {
+			state = cookie.Value
+		}
+	}
+	if state == "" {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+	// loginsrv checks the state against its cookie, which is sent by this server,
+	// so the server has to remember which states it issued
+	s.oauthStates.add(state, time.Now().Add(oauthStateLifetime))
+
+	return &StartOAuthLoginReply{
+		AuthorizationUrl: location.String(),
+		State:            state,
+	}, nil
+}
+
+// CompleteOAuthLogin exchanges the code of the provider callback for a loginsrv token,
+// the state is used up once loginsrv accepted or rejected it, so a failed request can be retried
+func (s *LoginSrvServer) CompleteOAuthLogin(ctx context.Context, request *CompleteOAuthLoginRequest) (*LoginReply, error) {
+	if !providerNamePattern.MatchString(request.Provider) {
+		return nil, grpc.Errorf(codes.InvalidArgument, "invalid provider")
+	}
+	if request.Code == "" || request.State == "" {
+		return nil, grpc.Errorf(codes.InvalidArgument, "code and state are required")
+	}
+	if !s.oauthStates.has(request.State) {
+		return nil, grpc.Errorf(codes.PermissionDenied, "oauth state param could not be verified")
+	}
+
+	query := url.Values{}
+	query.Set("code", request.Code)
+	query.Set("state", request.State)
+
+	body, err := s.requestAPI(
+		"GET",
+		"/login/"+request.Provider+"?"+query.Encode(),
+		"jwt",
+		nil,
+		&http.Cookie{Name: oauthStateCookieName, Value: request.State},
+	)
+	if status.Code(err) == codes.PermissionDenied {
+		s.oauthStates.take(request.State)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !s.oauthStates.take(request.State) {
+		// a concurrent request used the state up
+		return nil, grpc.Errorf(codes.PermissionDenied, "oauth state param could not be verified")
+	}
+	return s.referenceReply(ctx, s.newLoginReply(*body))
+}
@@ -0,0 +1,110 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// NewLoginTokenSource turns the login flow of an AuthClient into an oauth2.TokenSource
+// the first token is obtained with the login request and refreshed afterwards,
+// when a refresh is rejected the source logs in again
+func NewLoginTokenSource(ctx context.Context, client AuthClient, login *LoginRequest) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(nil, &authTokenSource{
+		ctx:    ctx,
+		client: client,
+		login:  login,
+	})
+}
+
+// NewRefreshTokenSource turns the refresh flow of an AuthClient into an oauth2.TokenSource
+// starting from an already obtained access token
+func NewRefreshTokenSource(ctx context.Context, client AuthClient, accessToken string) oauth2.TokenSource {
+	return oauth2.ReuseTokenSource(newOAuth2Token(accessToken), &authTokenSource{
+		ctx:    ctx,
+		client: client,
+		token:  accessToken,
+	})
+}
+
+// TokenGetterFromSource feeds the tokens of an oauth2.TokenSource to NewClientTokenInterceptor
+// the RPC is sent without a token when the source fails
+func TokenGetterFromSource(source oauth2.TokenSource) TokenGetter {
+	return func() *string {
+		token, err := source.Token()
+		if err != nil || token.AccessToken == "" {
+			return nil
+		}
+		return &token.AccessToken
+	}
+}
+
+// ContextTokenGetterFromSource feeds the tokens of an oauth2.TokenSource to NewClientContextTokenInterceptor
+// the RPC fails with Unauthenticated when the source fails
+func ContextTokenGetterFromSource(source oauth2.TokenSource) ContextTokenGetter {
+	return func(ctx context.Context, method string) (string, error) {
+		token, err := source.Token()
+		if err != nil {
+			return "", err
+		}
+		return token.AccessToken, nil
+	}
+}
+
+type authTokenSource struct {
+	ctx    context.Context
+	client AuthClient
+	login  *LoginRequest
+
+	mu    sync.Mutex
+	token string
+}
+
+// Token is only called by the reuse wrapper once the previous token expired
+func (ts *authTokenSource) Token() (*oauth2.Token, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	reply, err := ts.next()
+	if err != nil {
+		return nil, err
+	}
+
+	ts.token = reply.AccessToken
+	token := newOAuth2Token(reply.AccessToken)
+	if reply.ExpiresAt != 0 {
+		token.Expiry = time.Unix(reply.ExpiresAt, 0)
+	}
+	return token, nil
+}
+
+func (ts *authTokenSource) next() (*LoginReply, error) {
+	if ts.token == "" {
+		return ts.client.AttemptLogin(ts.ctx, ts.login)
+	}
+
+	reply, err := ts.client.RefreshToken(withOutgoingToken(ts.ctx, ts.token), &RefreshRequest{})
+	if err != nil && ts.login != nil {
+		return ts.client.AttemptLogin(ts.ctx, ts.login)
+	}
+	return reply, err
+}
+
+func newOAuth2Token(accessToken string) *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken: accessToken,
+		TokenType:   "Bearer",
+		Expiry:      tokenExpiry(accessToken),
+	}
+}
+
+// tokenExpiry returns the zero time when the expiry is unknown
+func tokenExpiry(token string) time.Time {
+	claims, err := ParseUnverifiedClaims(token)
+	if err != nil {
+		return time.Time{}
+	}
+	return claims.ExpiresAt()
+}
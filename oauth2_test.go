@@ -0,0 +1,66 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestLoginTokenSourceFillsExpiry(t *testing.T) {
+	exp := time.Now().Add(time.Hour).Unix()
+	client := &authClientStub{loginToken: unsignedTestToken(map[string]interface{}{"sub": "bob", "exp": exp})}
+	source := NewLoginTokenSource(context.Background(), client, &LoginRequest{Username: "bob", Password: "secret"})
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatal("Token failed", err)
+	}
+	if token.Expiry.Unix() != exp {
+		t.Errorf("Expected expiry %d but got %d", exp, token.Expiry.Unix())
+	}
+
+	if _, err := source.Token(); err != nil {
+		t.Fatal("Token failed", err)
+	}
+	if client.logins != 1 {
+		t.Errorf("Valid token should be reused but logged in %d times", client.logins)
+	}
+}
+
+func TestRefreshTokenSourceRefreshesExpiredToken(t *testing.T) {
+	expired := unsignedTestToken(map[string]interface{}{"sub": "bob", "exp": time.Now().Add(-time.Minute).Unix()})
+	fresh := unsignedTestToken(map[string]interface{}{"sub": "bob", "exp": time.Now().Add(time.Hour).Unix()})
+	client := &authClientStub{refreshToken: fresh}
+	source := NewRefreshTokenSource(context.Background(), client, expired)
+
+	token, err := source.Token()
+	if err != nil {
+		t.Fatal("Token failed", err)
+	}
+	if token.AccessToken != fresh {
+		t.Error("Expired token should be refreshed")
+	}
+	if client.lastToken != expired {
+		t.Error("Refresh should send the previous token")
+	}
+}
+
+func TestTokenGetterFromSource(t *testing.T) {
+	getter := TokenGetterFromSource(oauth2.StaticTokenSource(&oauth2.Token{AccessToken: "abc"}))
+
+	token := getter()
+	if token == nil || *token != "abc" {
+		t.Error("Getter should return the source token")
+	}
+}
+
+func unsignedTestToken(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "typ": "JWT"})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(payload) + "."
+}
@@ -0,0 +1,122 @@
+package loginsrv_grpc
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOAuthLogin(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	started, err := srv.StartOAuthLogin(nil, &StartOAuthLoginRequest{Provider: "github"})
+	if err != nil {
+		t.Fatal("StartOAuthLogin failed", err)
+	}
+	if started.State == "" {
+		t.Error("State should be returned")
+	}
+
+	// the user agent authorizes on the provider which redirects back with the code
+	callback := authorizeOrFail(t, started.AuthorizationUrl)
+	if callback.Query().Get("state") != started.State {
+		t.Error("Provider should echo the state")
+	}
+
+	reply, err := srv.CompleteOAuthLogin(nil, &CompleteOAuthLoginRequest{
+		Provider: "github",
+		Code:     callback.Query().Get("code"),
+		State:    started.State,
+	})
+	if err != nil {
+		t.Fatal("CompleteOAuthLogin failed", err)
+	}
+	claims, _ := reply.UnverifiedClaims()
+	if claims == nil || claims.Sub != "octocat" || claims.Origin != "github" {
+		t.Errorf("Expected a github token for octocat but got %v", claims)
+	}
+}
+
+func TestOAuthLoginRejectsWrongState(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	started, err := srv.StartOAuthLogin(nil, &StartOAuthLoginRequest{Provider: "github"})
+	if err != nil {
+		t.Fatal("StartOAuthLogin failed", err)
+	}
+	callback := authorizeOrFail(t, started.AuthorizationUrl)
+
+	_, err = srv.CompleteOAuthLogin(nil, &CompleteOAuthLoginRequest{
+		Provider: "github",
+		Code:     callback.Query().Get("code"),
+		State:    "forged",
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Error("Forged state should be rejected", err)
+	}
+}
+
+func TestOAuthLoginKeepsStateOnTransientFailure(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	started, err := srv.StartOAuthLogin(nil, &StartOAuthLoginRequest{Provider: "github"})
+	if err != nil {
+		t.Fatal("StartOAuthLogin failed", err)
+	}
+	callback := authorizeOrFail(t, started.AuthorizationUrl)
+	request := &CompleteOAuthLoginRequest{
+		Provider: "github",
+		Code:     callback.Query().Get("code"),
+		State:    started.State,
+	}
+
+	upstream.oauthFailures = 1
+	if _, err := srv.CompleteOAuthLogin(nil, request); err == nil || status.Code(err) == codes.PermissionDenied {
+		t.Fatal("Expected a transient failure but got", err)
+	}
+	if _, err := srv.CompleteOAuthLogin(nil, request); err != nil {
+		t.Fatal("State should survive a transient failure", err)
+	}
+	if _, err := srv.CompleteOAuthLogin(nil, request); status.Code(err) != codes.PermissionDenied {
+		t.Error("State should be used up by a successful login", err)
+	}
+}
+
+func TestStartOAuthLoginValidatesProvider(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	if _, err := srv.StartOAuthLogin(nil, &StartOAuthLoginRequest{Provider: "../admin"}); status.Code(err) != codes.InvalidArgument {
+		t.Error("Provider should be validated", err)
+	}
+	if _, err := srv.StartOAuthLogin(nil, &StartOAuthLoginRequest{Provider: "gitlab"}); status.Code(err) != codes.NotFound {
+		t.Error("Unknown provider should not be found", err)
+	}
+}
+
+func authorizeOrFail(t *testing.T, authorizationURL string) *url.URL {
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authorizationURL)
+	if err != nil {
+		t.Fatal("Authorization failed", err)
+	}
+	resp.Body.Close()
+
+	callback, err := resp.Location()
+	if err != nil {
+		t.Fatal("Provider should redirect to the callback", err)
+	}
+	return callback
+}
@@ -0,0 +1,183 @@
+package loginsrv_grpc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// OIDCHandler is a minimal OpenID Connect provider facade, mount it at the root of the issuer
+//
+// it serves the discovery document, the keys validating the tokens
+// and a userinfo endpoint backed by GetProfile, for token validation and userinfo only:
+// no authorization flow is advertised, tokens are still obtained with AttemptLogin,
+// tokens of loginsrv can only be validated by relying parties when loginsrv signs them
+// with an RSA or ECDSA key, shared secrets are never published,
+// and they carry no iss nor aud, so relying parties checking the issuer or the audience reject them
+type OIDCHandler struct {
+	srv    *LoginSrvServer
+	issuer string
+	keys   []jsonWebKey
+}
+
+// OIDCOption allows functional configuration for the OIDCHandler
+type OIDCOption func(*OIDCHandler)
+
+// WithOIDCKey publishes a public key validating tokens, such as the one of the -jwt-algo of loginsrv,
+// the algorithm is the alg of the tokens, e.g. RS256 or ES256
+func WithOIDCKey(key crypto.PublicKey, keyID string, algorithm string) OIDCOption {
+	return func(h *OIDCHandler) {
+		if jwk, ok := newJSONWebKey(key, keyID, algorithm); ok {
+			h.keys = append(h.keys, jwk)
+		}
+	}
+}
+
+// NewOIDCHandler creates the facade of the issuer url,
+// the signing key of the server is always published, the server should name the same issuer with WithIssuer
+func NewOIDCHandler(srv *LoginSrvServer, issuer string, options ...OIDCOption) *OIDCHandler {
+	h := &OIDCHandler{
+		srv:    srv,
+		issuer: strings.TrimSuffix(issuer, "/"),
+		keys:   []jsonWebKey{},
+	}
+	if k := srv.signingKey; k != nil {
+		if jwk, ok := newJSONWebKey(k.key.Public(), k.keyID, k.algorithm()); ok {
+			h.keys = append(h.keys, jwk)
+		}
+	}
+
+	for i := range options {
+		options[i](h)
+	}
+	return h
+}
+
+func (h *OIDCHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/.well-known/openid-configuration":
+		h.serveConfiguration(w, r)
+	case "/jwks":
+		writeJSON(w, map[string]interface{}{"keys": h.keys})
+	case "/userinfo":
+		h.serveUserInfo(w, r)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *OIDCHandler) serveConfiguration(w http.ResponseWriter, r *http.Request) {
+	// RS256 is required by the discovery spec
+	algorithms := []string{"RS256"}
+	for _, key := range h.keys {
+		if key.Algorithm != "RS256" {
+			algorithms = append(algorithms, key.Algorithm)
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{
+		"issuer":                                h.issuer,
+		"jwks_uri":                              h.issuer + "/jwks",
+		"userinfo_endpoint":                     h.issuer + "/userinfo",
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": algorithms,
+		"claims_supported":                      []string{"sub", "name", "email", "picture", "groups", "domain"},
+	})
+}
+
+// serveUserInfo answers with the standard claims of the profile of the bearer token
+func (h *OIDCHandler) serveUserInfo(w http.ResponseWriter, r *http.Request) {
+	token := bearerToken(r)
+	if token == "" {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "missing token", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := md.NewIncomingContext(r.Context(), md.Pairs(AuthTokenMetadataKey, "bearer "+token))
+	profile, err := h.srv.GetProfile(ctx, &ProfileRequest{})
+	if err != nil {
+		code := status.Code(err)
+		if code == codes.PermissionDenied || code == codes.InvalidArgument || code == codes.Unauthenticated {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+		writeHTTPError(w, err)
+		return
+	}
+
+	userInfo := map[string]interface{}{"sub": profile.Sub}
+	for name, value := range map[string]string{
+		"name":    profile.Name,
+		"email":   profile.Email,
+		"picture": profile.Picture,
+		"domain":  profile.Domain,
+	} {
+		if value != "" {
+			userInfo[name] = value
+		}
+	}
+	if len(profile.Groups) > 0 {
+		userInfo["groups"] = profile.Groups
+	}
+	writeJSON(w, userInfo)
+}
+
+func writeJSON(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(value)
+}
+
+// jsonWebKey is the RFC 7517 form of an RSA or EC public key
+type jsonWebKey struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	KeyID     string `json:"kid,omitempty"`
+	Algorithm string `json:"alg"`
+
+	N string `json:"n,omitempty"`
+	E string `json:"e,omitempty"`
+
+	Curve string `json:"crv,omitempty"`
+	X     string `json:"x,omitempty"`
+	Y     string `json:"y,omitempty"`
+}
+
+func newJSONWebKey(key crypto.PublicKey, keyID string, algorithm string) (jsonWebKey, bool) {
+	jwk := jsonWebKey{Use: "sig", KeyID: keyID, Algorithm: algorithm}
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		jwk.KeyType = "EC"
+		jwk.Curve = key.Curve.Params().Name
+		jwk.X = base64.RawURLEncoding.EncodeToString(padBytes(key.X.Bytes(), size))
+		jwk.Y = base64.RawURLEncoding.EncodeToString(padBytes(key.Y.Bytes(), size))
+	default:
+		return jwk, false
+	}
+	return jwk, true
+}
+
+// padBytes left pads the big endian value to size bytes
+func padBytes(value []byte, size int) []byte {
+	if len(value) >= size {
+		return value
+	}
+	padded := make([]byte, size)
+	copy(padded[size-len(value):], value)
+	return padded
+}
@@ -0,0 +1,113 @@
+package loginsrv_grpc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func serveOIDC(t *testing.T, h http.Handler, path string, token string, value interface{}) int {
+	req := httptest.NewRequest("GET", path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code == http.StatusOK {
+		if err := json.NewDecoder(w.Body).Decode(value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w.Code
+}
+
+func TestOIDCDiscoveryAndKeys(t *testing.T) {
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	srv := NewLoginSrvServer("http://loginsrv:8080", WithSigningKey(ecKey, "exchange-1"))
+	h := NewOIDCHandler(srv, "https://auth.example.com/", WithOIDCKey(&rsaKey.PublicKey, "loginsrv-1", "RS512"))
+
+	configuration := map[string]interface{}{}
+	serveOIDC(t, h, "/.well-known/openid-configuration", "", &configuration)
+	if configuration["issuer"] != "https://auth.example.com" ||
+		configuration["jwks_uri"] != "https://auth.example.com/jwks" ||
+		configuration["userinfo_endpoint"] != "https://auth.example.com/userinfo" {
+		t.Errorf("Unexpected configuration %v", configuration)
+	}
+	if _, ok := configuration["authorization_endpoint"]; ok {
+		t.Error("No authorization flow should be advertised")
+	}
+	expected := []interface{}{"RS256", "ES256", "RS512"}
+	if !reflect.DeepEqual(configuration["id_token_signing_alg_values_supported"], expected) {
+		t.Errorf("Expected the algorithms %v but got %v", expected, configuration["id_token_signing_alg_values_supported"])
+	}
+
+	jwks := struct {
+		Keys []jsonWebKey `json:"keys"`
+	}{}
+	serveOIDC(t, h, "/jwks", "", &jwks)
+	if len(jwks.Keys) != 2 || jwks.Keys[0].KeyID != "exchange-1" || jwks.Keys[1].Algorithm != "RS512" {
+		t.Fatalf("Unexpected keys %+v", jwks.Keys)
+	}
+
+	n, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[1].N)
+	if new(big.Int).SetBytes(n).Cmp(rsaKey.N) != 0 || jwks.Keys[1].E != "AQAB" {
+		t.Error("RSA key should be published as is")
+	}
+
+	// a relying party rebuilding the key validates the tokens signed by the server
+	x, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+	y, _ := base64.RawURLEncoding.DecodeString(jwks.Keys[0].Y)
+	published := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+	token, err := srv.signingKey.sign(map[string]interface{}{"sub": "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier := &signingKey{key: &ecdsa.PrivateKey{PublicKey: *published}, keyID: jwks.Keys[0].KeyID}
+	if claims, err := verifier.verify(token); err != nil || claims.Sub != "bob" || jwks.Keys[0].Algorithm != "ES256" {
+		t.Error("Published key should validate the tokens", err)
+	}
+}
+
+func TestOIDCWithoutKeys(t *testing.T) {
+	h := NewOIDCHandler(NewLoginSrvServer("http://loginsrv:8080"), "https://auth.example.com")
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/jwks", nil))
+	if body := strings.TrimSpace(w.Body.String()); body != `{"keys":[]}` {
+		t.Errorf("Key set should be empty rather than null but got %s", body)
+	}
+}
+
+func TestOIDCUserInfo(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	h := NewOIDCHandler(srv, "https://auth.example.com")
+
+	userInfo := map[string]interface{}{}
+	if code := serveOIDC(t, h, "/userinfo", obtainTokenOrFail(t, srv), &userInfo); code != http.StatusOK {
+		t.Fatalf("Userinfo should be served but got %d", code)
+	}
+	if userInfo["sub"] != "bob" || userInfo["email"] != nil {
+		t.Errorf("Unexpected userinfo %v", userInfo)
+	}
+	if groups, _ := userInfo["groups"].([]interface{}); len(groups) != 1 || groups[0] != "dev" {
+		t.Errorf("Groups should be part of the userinfo, got %v", userInfo["groups"])
+	}
+
+	for _, token := range []string{"", "garbage"} {
+		if code := serveOIDC(t, h, "/userinfo", token, nil); code != http.StatusUnauthorized {
+			t.Errorf("Token %q should be rejected but got %d", token, code)
+		}
+	}
+}
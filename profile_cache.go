@@ -0,0 +1,58 @@
+package loginsrv_grpc
+
+import (
+	"sync"
+	"time"
+)
+
+// profileCache remembers the profiles of valid tokens for a while,
+// so repeated checks of a token skip loginsrv
+type profileCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*profileCacheEntry
+}
+
+type profileCacheEntry struct {
+	profile *Profile
+	until   time.Time
+}
+
+func newProfileCache(ttl time.Duration) *profileCache {
+	return &profileCache{ttl: ttl, entries: map[string]*profileCacheEntry{}}
+}
+
+func (c *profileCache) get(token string) (*Profile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[token]
+	if !ok || !time.Now().Before(entry.until) {
+		return nil, false
+	}
+	return entry.profile, true
+}
+
+// add keeps the profile for the ttl, or until the token expires when it is sooner
+func (c *profileCache) add(token string, profile *Profile) {
+	until := time.Now().Add(c.ttl)
+	if profile.Expiry != 0 && time.Unix(profile.Expiry, 0).Before(until) {
+		until = time.Unix(profile.Expiry, 0)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.purge()
+	c.entries[token] = &profileCacheEntry{profile: profile, until: until}
+}
+
+// purge drops the outdated entries, it must be called with mu held
+func (c *profileCache) purge() {
+	now := time.Now()
+	for token, entry := range c.entries {
+		if !now.Before(entry.until) {
+			delete(c.entries, token)
+		}
+	}
+}
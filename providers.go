@@ -0,0 +1,173 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// providerDiscoveryTTL is how long the providers discovered upstream are cached
+const providerDiscoveryTTL = 5 * time.Minute
+
+// oauthProviders are the oauth providers supported by loginsrv
+var oauthProviders = []*LoginProvider{
+	RedirectProvider("github", "GitHub"),
+	RedirectProvider("google", "Google"),
+	RedirectProvider("bitbucket", "Bitbucket"),
+	RedirectProvider("facebook", "Facebook"),
+	RedirectProvider("gitlab", "GitLab"),
+}
+
+// PasswordProvider declares a loginsrv backend checking a username and a password,
+// like simple, htpasswd or httpupstream, loginsrv tries all of its backends whichever is named
+func PasswordProvider(name string, displayName string) *LoginProvider {
+	return &LoginProvider{Name: name, DisplayName: displayName, Flow: LoginProvider_PASSWORD}
+}
+
+// RedirectProvider declares a loginsrv oauth provider
+func RedirectProvider(name string, displayName string) *LoginProvider {
+	return &LoginProvider{Name: name, DisplayName: displayName, Flow: LoginProvider_REDIRECT}
+}
+
+// WithLoginProviders declares the login backends configured on loginsrv
+func WithLoginProviders(providers ...*LoginProvider) Option {
+	return func(s *LoginSrvServer) {
+		s.providers = append(s.providers, providers...)
+	}
+}
+
+// WithProviderDiscovery lists the oauth providers found on loginsrv along the declared ones
+func WithProviderDiscovery() Option {
+	return func(s *LoginSrvServer) {
+		s.discovery = &providerDiscovery{}
+	}
+}
+
+// ListProviders returns the login providers so frontends can render the login screen
+func (s *LoginSrvServer) ListProviders(ctx context.Context, request *ListProvidersRequest) (*ListProvidersReply, error) {
+	providers := append([]*LoginProvider{}, s.providers...)
+	if s.discovery != nil {
+		for _, discovered := range s.discovery.get(s) {
+			if s.provider(discovered.Name) == nil {
+				providers = append(providers, discovered)
+			}
+		}
+	}
+	return &ListProvidersReply{Providers: providers}, nil
+}
+
+// loginPath returns the loginsrv path a password provider posts to
+// loginsrv runs all of its password backends for any path below /login,
+// so the path only names the provider, it does not select a backend
+func (s *LoginSrvServer) loginPath(name string) (string, error) {
+	if name == "" {
+		return "/login", nil
+	}
+
+	provider := s.provider(name)
+	if provider == nil || !providerNamePattern.MatchString(name) {
+		return "", grpc.Errorf(codes.InvalidArgument, "unknown provider")
+	}
+	if provider.Flow != LoginProvider_PASSWORD {
+		return "", grpc.Errorf(codes.FailedPrecondition, "provider %q logs in with startOAuthLogin", name)
+	}
+	return "/login/" + name, nil
+}
+
+// provider returns the declared provider with the given name
+func (s *LoginSrvServer) provider(name string) *LoginProvider {
+	for _, provider := range s.providers {
+		if provider.Name == name {
+			return provider
+		}
+	}
+	return nil
+}
+
+// providerDiscovery caches the oauth providers answering on loginsrv,
+// loginsrv is probed outside of the lock by a single caller at a time
+type providerDiscovery struct {
+	mu           sync.Mutex
+	providers    []*LoginProvider
+	discoveredAt time.Time
+	running      *discoveryCall
+}
+
+// discoveryCall shares a running discovery with the concurrent callers
+type discoveryCall struct {
+	done      chan struct{}
+	providers []*LoginProvider
+}
+
+func (d *providerDiscovery) get(s *LoginSrvServer) []*LoginProvider {
+	d.mu.Lock()
+	if time.Since(d.discoveredAt) < providerDiscoveryTTL {
+		defer d.mu.Unlock()
+		return d.providers
+	}
+	if call := d.running; call != nil {
+		d.mu.Unlock()
+		<-call.done
+		return call.providers
+	}
+	call := &discoveryCall{done: make(chan struct{})}
+	d.running = call
+	d.mu.Unlock()
+
+	providers, err := s.discoverOAuthProviders()
+
+	d.mu.Lock()
+	// a failed discovery is retried by the next caller
+	if err == nil {
+		d.providers, d.discoveredAt = providers, time.Now()
+	}
+	d.running = nil
+	d.mu.Unlock()
+
+	call.providers = providers
+	close(call.done)
+	return providers
+}
+
+// discoverOAuthProviders returns the oauth providers answering on loginsrv,
+// along with the error of the first probe loginsrv did not answer
+func (s *LoginSrvServer) discoverOAuthProviders() ([]*LoginProvider, error) {
+	var firstErr error
+	providers := []*LoginProvider{}
+	for _, provider := range oauthProviders {
+		ok, err := s.hasOAuthProvider(provider.Name)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if ok {
+			providers = append(providers, provider)
+		}
+	}
+	return providers, firstErr
+}
+
+// hasOAuthProvider tells whether loginsrv starts an oauth flow for the provider
+// it fails when loginsrv cannot be reached or answers with a server error
+func (s *LoginSrvServer) hasOAuthProvider(name string) (bool, error) {
+	req, err := http.NewRequest("GET", *s.baseURL+"/login/"+name, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := s.noRedirectClient().Do(req)
+	if err != nil {
+		return false, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 500 {
+		return false, fmt.Errorf("loginsrv answered %s", resp.Status)
+	}
+
+	location, err := resp.Location()
+	return err == nil && location.Query().Get("state") != "", nil
+}
@@ -0,0 +1,127 @@
+package loginsrv_grpc
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestListProviders(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(
+		upstream.URL,
+		WithLoginProviders(
+			PasswordProvider("simple", "Username and password"),
+			RedirectProvider("github", "GitHub Enterprise"),
+		),
+		WithProviderDiscovery(),
+	)
+
+	reply, err := srv.ListProviders(nil, &ListProvidersRequest{})
+	if err != nil {
+		t.Fatal("ListProviders failed", err)
+	}
+
+	if len(reply.Providers) != 2 {
+		t.Fatalf("Expected the 2 declared providers but got %v", reply.Providers)
+	}
+	if reply.Providers[0].Flow != LoginProvider_PASSWORD || reply.Providers[1].Flow != LoginProvider_REDIRECT {
+		t.Errorf("Unexpected flows %v", reply.Providers)
+	}
+	if reply.Providers[1].DisplayName != "GitHub Enterprise" {
+		t.Error("Declared provider should take precedence over the discovered one")
+	}
+}
+
+func TestListProvidersDiscoversOAuthProviders(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithProviderDiscovery())
+
+	reply, err := srv.ListProviders(nil, &ListProvidersRequest{})
+	if err != nil {
+		t.Fatal("ListProviders failed", err)
+	}
+
+	if len(reply.Providers) != 1 || reply.Providers[0].Name != "github" {
+		t.Errorf("Expected github to be discovered but got %v", reply.Providers)
+	}
+}
+
+func TestListProvidersRetriesFailedDiscovery(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	var failing int32 = 1
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		upstream.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer flaky.Close()
+	srv := NewLoginSrvServer(flaky.URL, WithProviderDiscovery())
+
+	reply, err := srv.ListProviders(nil, &ListProvidersRequest{})
+	if err != nil || len(reply.Providers) != 0 {
+		t.Fatalf("Expected no provider while loginsrv fails but got %v %v", reply, err)
+	}
+
+	atomic.StoreInt32(&failing, 0)
+	reply, err = srv.ListProviders(nil, &ListProvidersRequest{})
+	if err != nil || len(reply.Providers) != 1 {
+		t.Errorf("Failed discovery should not be cached but got %v %v", reply, err)
+	}
+}
+
+func TestAttemptLoginRoutesProviderAndExtraFields(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(
+		upstream.URL,
+		WithLoginProviders(PasswordProvider("simple", "Simple"), RedirectProvider("github", "GitHub")),
+	)
+
+	_, err := srv.AttemptLogin(nil, &LoginRequest{
+		Username: "bob",
+		Password: "secret",
+		Provider: "simple",
+		Extra:    map[string]string{"domain": "example.com", "otp": "12 34&x=y"},
+	})
+	if err != nil {
+		t.Fatal("Login failed", err)
+	}
+	if upstream.lastPath != "/login/simple" {
+		t.Error("Login should be posted to the provider path but got " + upstream.lastPath)
+	}
+	if upstream.lastForm.Get("domain") != "example.com" || upstream.lastForm.Get("otp") != "12 34&x=y" || upstream.lastForm.Get("x") != "" {
+		t.Errorf("Extra fields should be encoded safely but got %v", upstream.lastForm)
+	}
+
+	_, err = srv.AttemptLogin(nil, &LoginRequest{Username: "bob", Password: "wrong&password=secret"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Error("Password should not inject form fields", err)
+	}
+}
+
+func TestAttemptLoginRejectsInvalidProviders(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithLoginProviders(RedirectProvider("github", "GitHub")))
+
+	requests := map[*LoginRequest]codes.Code{
+		{Username: "bob", Password: "secret", Provider: "htpasswd"}:                          codes.InvalidArgument,
+		{Username: "bob", Password: "secret", Provider: "github"}:                            codes.FailedPrecondition,
+		{Username: "bob", Password: "secret", Extra: map[string]string{"username": "admin"}}: codes.InvalidArgument,
+	}
+	for request, code := range requests {
+		if _, err := srv.AttemptLogin(nil, request); status.Code(err) != code {
+			t.Errorf("Expected %v for %v but got %v", code, request, err)
+		}
+	}
+}
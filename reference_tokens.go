@@ -0,0 +1,169 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// referenceHandleSize is the number of random bytes of a handle
+const referenceHandleSize = 32
+
+// ErrUnknownReference is returned by a TokenStore for handles it does not know or that expired
+var ErrUnknownReference = errors.New("unknown reference token")
+
+// TokenStore keeps the tokens of loginsrv behind the opaque handles given to the clients
+type TokenStore interface {
+	// Put stores the token under the handle until the expiry, a zero expiry never expires
+	Put(ctx context.Context, handle string, token string, expiry time.Time) error
+	// Get returns the token of the handle or ErrUnknownReference
+	Get(ctx context.Context, handle string) (string, error)
+	// Delete forgets the handle, deleting an unknown handle is not an error
+	Delete(ctx context.Context, handle string) error
+}
+
+// WithReferenceTokens hands opaque handles to the clients instead of the tokens of loginsrv,
+// login replies carry no profile, the tokens stay in the store
+// and the handles are resolved wherever a token is accepted,
+// tokens of loginsrv are no longer accepted from the clients
+func WithReferenceTokens(store TokenStore) Option {
+	return func(s *LoginSrvServer) {
+		s.tokens = store
+	}
+}
+
+// referenceReply replaces the token of the reply with a new handle
+// and drops the profile, the claims of the token stay on the server
+func (s *LoginSrvServer) referenceReply(ctx context.Context, reply *LoginReply) (*LoginReply, error) {
+	if s.tokens == nil {
+		return reply, nil
+	}
+	handle, err := s.storeReference(ctx, reply.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	reply.AccessToken = handle
+	reply.Profile = nil
+	return reply, nil
+}
+
+// referenceEvent hands out a handle of a refreshed token, the events of the bus are shared
+// between the watchers so a copy is returned
+func (s *LoginSrvServer) referenceEvent(ctx context.Context, event *SessionEvent) (*SessionEvent, error) {
+	if s.tokens == nil || event.Type != SessionEvent_TOKEN_REFRESHED {
+		return event, nil
+	}
+	handle, err := s.storeReference(ctx, event.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionEvent{
+		Type:        event.Type,
+		AccessToken: handle,
+		ExpiresAt:   event.ExpiresAt,
+		Message:     event.Message,
+	}, nil
+}
+
+func (s *LoginSrvServer) storeReference(ctx context.Context, token string) (string, error) {
+	random := make([]byte, referenceHandleSize)
+	if _, err := rand.Read(random); err != nil {
+		return "", grpc.Errorf(codes.Internal, "Internal")
+	}
+	handle := base64.RawURLEncoding.EncodeToString(random)
+
+	if err := s.tokens.Put(ctx, handle, token, tokenExpiry(token)); err != nil {
+		return "", grpc.Errorf(codes.Unavailable, "token store unavailable")
+	}
+	return handle, nil
+}
+
+// resolveToken returns the token of loginsrv behind a handle,
+// tokens are returned as they are when reference tokens are disabled
+func (s *LoginSrvServer) resolveToken(ctx context.Context, handle string) (string, error) {
+	if s.tokens == nil || handle == "" {
+		return handle, nil
+	}
+	token, err := s.tokens.Get(ctx, handle)
+	if err == ErrUnknownReference {
+		return "", grpc.Errorf(codes.Unauthenticated, "invalid token")
+	}
+	if err != nil {
+		return "", grpc.Errorf(codes.Unavailable, "token store unavailable")
+	}
+	return token, nil
+}
+
+// dropReference deletes the handle of the RPC, if any
+func (s *LoginSrvServer) dropReference(ctx context.Context) {
+	if s.tokens == nil {
+		return
+	}
+	if handle := s.presentedToken(ctx); handle != nil {
+		// the token behind it is revoked anyway
+		_ = s.tokens.Delete(ctx, *handle)
+	}
+}
+
+// memoryTokenStore is a TokenStore for a single server
+type memoryTokenStore struct {
+	mu      sync.Mutex
+	entries map[string]*memoryTokenEntry
+}
+
+type memoryTokenEntry struct {
+	token  string
+	expiry time.Time
+}
+
+// NewMemoryTokenStore creates a TokenStore keeping the tokens in memory,
+// handles do not survive restarts nor work across replicas
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{entries: map[string]*memoryTokenEntry{}}
+}
+
+func (m *memoryTokenStore) Put(ctx context.Context, handle string, token string, expiry time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.purge()
+	m.entries[handle] = &memoryTokenEntry{token: token, expiry: expiry}
+	return nil
+}
+
+func (m *memoryTokenStore) Get(ctx context.Context, handle string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[handle]
+	if !ok || entry.expired(time.Now()) {
+		return "", ErrUnknownReference
+	}
+	return entry.token, nil
+}
+
+func (m *memoryTokenStore) Delete(ctx context.Context, handle string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, handle)
+	return nil
+}
+
+// purge drops the expired entries, it must be called with mu held
+func (m *memoryTokenStore) purge() {
+	now := time.Now()
+	for handle, entry := range m.entries {
+		if entry.expired(now) {
+			delete(m.entries, handle)
+		}
+	}
+}
+
+func (e *memoryTokenEntry) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
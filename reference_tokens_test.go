@@ -0,0 +1,103 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestReferenceTokens(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	store := NewMemoryTokenStore()
+	srv := NewLoginSrvServer(upstream.URL, WithReferenceTokens(store))
+
+	login, err := srv.AttemptLogin(nil, &LoginRequest{Username: "bob", Password: "secret"})
+	if err != nil {
+		t.Fatal("Login failed", err)
+	}
+	handle := login.AccessToken
+	if _, err := ParseUnverifiedClaims(handle); err == nil {
+		t.Fatal("Login should return an opaque handle")
+	}
+	if login.Profile != nil || login.ExpiresAt == 0 {
+		t.Errorf("Login should keep the expiry but not the profile, got %v", login)
+	}
+	token, err := store.Get(context.Background(), handle)
+	if err != nil || tokenExpiry(token).IsZero() {
+		t.Fatal("Token of loginsrv should be kept in the store", err)
+	}
+
+	ctx, err := srv.Authenticate(&contextWithAuthorizationStub{authToken: handle})
+	if err != nil {
+		t.Fatal("Handle should authenticate", err)
+	}
+	if profile, _ := ProfileFromContext(ctx); profile.GetSub() != "bob" {
+		t.Errorf("Expected the profile of bob but got %v", profile)
+	}
+	if _, err := srv.Authenticate(&contextWithAuthorizationStub{authToken: token}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Tokens of loginsrv should not be accepted from clients", err)
+	}
+
+	refreshed, err := srv.RefreshToken(&contextWithAuthorizationStub{authToken: handle}, &RefreshRequest{})
+	if err != nil {
+		t.Fatal("Refresh failed", err)
+	}
+	if refreshed.AccessToken == handle || refreshed.Profile != nil {
+		t.Errorf("Refresh should return a new handle without profile but got %v", refreshed)
+	}
+	if _, err := ParseUnverifiedClaims(refreshed.AccessToken); err == nil {
+		t.Error("Refresh should return an opaque handle")
+	}
+
+	if _, err := srv.Logout(&contextWithAuthorizationStub{authToken: refreshed.AccessToken}, &LogoutRequest{}); err != nil {
+		t.Fatal("Logout failed", err)
+	}
+	if _, err := store.Get(context.Background(), refreshed.AccessToken); err != ErrUnknownReference {
+		t.Error("Logout should delete the handle", err)
+	}
+	if _, err := srv.GetProfile(&contextWithAuthorizationStub{authToken: refreshed.AccessToken}, &ProfileRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Handle should not work after logout", err)
+	}
+}
+
+func TestWatchSessionPushesReferenceHandle(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	upstream.lifetime = 3 * time.Second
+	store := NewMemoryTokenStore()
+	srv := NewLoginSrvServer(upstream.URL, WithReferenceTokens(store), WithSessionRefreshBefore(2*time.Second))
+	handle := obtainTokenOrFail(t, srv)
+
+	stream := newWatchStreamStub(handle)
+	defer stream.cancel()
+	go srv.WatchSession(&WatchSessionRequest{}, stream)
+
+	event := stream.next(t)
+	if event.Type != SessionEvent_TOKEN_REFRESHED || event.AccessToken == handle {
+		t.Fatalf("Expected a refreshed handle but got %v", event)
+	}
+	if _, err := srv.Authenticate(&contextWithAuthorizationStub{authToken: event.AccessToken}); err != nil {
+		t.Error("Refreshed handle should authenticate", err)
+	}
+}
+
+func TestMemoryTokenStoreExpiry(t *testing.T) {
+	store := NewMemoryTokenStore()
+	ctx := context.Background()
+	store.Put(ctx, "expired", "token", time.Now().Add(-time.Second))
+	store.Put(ctx, "forever", "token", time.Time{})
+
+	if _, err := store.Get(ctx, "expired"); err != ErrUnknownReference {
+		t.Error("Expired handle should be unknown", err)
+	}
+	if token, err := store.Get(ctx, "forever"); err != nil || token != "token" {
+		t.Error("Handle without expiry should be kept", err)
+	}
+	if err := store.Delete(ctx, "missing"); err != nil {
+		t.Error("Deleting an unknown handle should succeed", err)
+	}
+}
@@ -0,0 +1,63 @@
+package loginsrv_grpc
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultRevocationRetention is used for tokens without an expiry
+const defaultRevocationRetention = 24 * time.Hour
+
+// expiringSet remembers values, such as revoked tokens, until they expire
+type expiringSet struct {
+	mu     sync.Mutex
+	values map[string]time.Time
+}
+
+func newExpiringSet() *expiringSet {
+	return &expiringSet{values: map[string]time.Time{}}
+}
+
+func (l *expiringSet) add(value string, expiry time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.purge()
+	l.values[value] = expiry
+}
+
+func (l *expiringSet) has(value string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiry, ok := l.values[value]
+	return ok && time.Now().Before(expiry)
+}
+
+// take removes the value and tells whether it was still there
+func (l *expiringSet) take(value string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	expiry, ok := l.values[value]
+	delete(l.values, value)
+	return ok && time.Now().Before(expiry)
+}
+
+// purge drops the expired values, it must be called with mu held
+func (l *expiringSet) purge() {
+	now := time.Now()
+	for value, expiry := range l.values {
+		if !now.Before(expiry) {
+			delete(l.values, value)
+		}
+	}
+}
+
+// revoke remembers a token until its expiry
+func (l *expiringSet) revoke(token string) {
+	expiry := tokenExpiry(token)
+	if expiry.IsZero() {
+		expiry = time.Now().Add(defaultRevocationRetention)
+	}
+	l.add(token, expiry)
+}
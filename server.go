@@ -2,10 +2,10 @@ package loginsrv_grpc
 
 import (
 	"context"
-	"encoding/json"
 	"io"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,11 +16,45 @@ import (
 	md "google.golang.org/grpc/metadata"
 )
 
+const (
+	// jwtCookieName is the cookie loginsrv keeps its token in
+	jwtCookieName = "jwt_token"
+	// setCookieMetadataKey is the header metadata key of cookies set by the server
+	setCookieMetadataKey = "set-cookie"
+)
+
 // LoginSrvServer proxies the REST api though grpc
 type LoginSrvServer struct {
 	UnimplementedAuthServer
 	apiClient *http.Client
 	baseURL   *string
+	revoked   *expiringSet
+
+	oauthStates *expiringSet
+
+	providers []*LoginProvider
+	discovery *providerDiscovery
+
+	events        *sessionBus
+	refreshBefore time.Duration
+	maxRefreshes  int
+
+	jwtSecret             []byte
+	lookups               *flightGroup
+	profiles              *profileCache
+	validationConcurrency int
+	introspectionPolicy   IntrospectionPolicy
+
+	authorizer Authorizer
+
+	cookieName string
+
+	signingKey       *signingKey
+	issuer           string
+	exchangePolicy   ExchangePolicy
+	exchangeLifetime time.Duration
+
+	tokens TokenStore
 }
 
 // AuthFuncOverride used internally to skip authentication for login route
@@ -28,32 +62,48 @@ func (s *LoginSrvServer) AuthFuncOverride(ctx context.Context, fullMethodName st
 	return ctx, nil
 }
 
-// Authenticate asserts a token is attached to the RPC context
-// clients can attach it with NewClientTokenInterceptor
+// Authenticate asserts a valid token is attached to the RPC context
+// and that the authorizer allows the RPC method,
+// clients can attach it with NewClientTokenInterceptor or as a cookie, see WithCookieToken,
+// the profile of the token is available to handlers through ProfileFromContext
 func (s *LoginSrvServer) Authenticate(ctx context.Context) (context.Context, error) {
-	accessToken, err := grpc_auth.AuthFromMD(ctx, "bearer")
-
-	if err != nil {
-		return nil, err
+	accessToken := s.cookieToken(ctx)
+	if accessToken == "" {
+		var err error
+		if accessToken, err = grpc_auth.AuthFromMD(ctx, "bearer"); err != nil {
+			return nil, err
+		}
 	}
 
 	// validate token on microservice
-	if len(accessToken) == 0 {
-		oldToken := getTokenFromContext(ctx)
-		if oldToken == nil {
-			return nil, grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
-		}
-		_, err = s.GetProfile(ctx, &ProfileRequest{})
-
-		return ctx, err
+	profile, err := s.authorize(ctx, accessToken, methodOf(ctx))
+	if err != nil {
+		return nil, err
 	}
 
-	return ctx, nil
+	return withProfile(ctx, profile), nil
 }
 
 // Option allows functional configuration for the loginServer
 type Option func(*LoginSrvServer)
 
+// WithMaxRefreshes mirrors the -jwt-refreshes flag of loginsrv
+// so login replies tell how many refreshes are left
+func WithMaxRefreshes(n int) Option {
+	return func(s *LoginSrvServer) {
+		s.maxRefreshes = n
+	}
+}
+
+// WithCookieToken lets RPCs without authorization metadata carry the token
+// in the named cookie of their cookie metadata, loginsrv names it jwt_token
+// the authorization metadata always takes precedence, even when its token is invalid
+func WithCookieToken(name string) Option {
+	return func(s *LoginSrvServer) {
+		s.cookieName = name
+	}
+}
+
 // NewLoginSrvServer creates the AuthServer
 func NewLoginSrvServer(url string, options ...Option) *LoginSrvServer {
 	srv := &LoginSrvServer{
@@ -61,6 +111,15 @@ func NewLoginSrvServer(url string, options ...Option) *LoginSrvServer {
 		apiClient: &http.Client{
 			Timeout: time.Second * 30,
 		},
+		revoked:     newExpiringSet(),
+		oauthStates: newExpiringSet(),
+		events:      newSessionBus(),
+		lookups:     newFlightGroup(),
+
+		refreshBefore:         time.Minute,
+		maxRefreshes:          -1,
+		validationConcurrency: 8,
+		exchangeLifetime:      5 * time.Minute,
 	}
 
 	for i := range options {
@@ -70,33 +129,85 @@ func NewLoginSrvServer(url string, options ...Option) *LoginSrvServer {
 }
 
 // AttemptLogin is a basic authentication
+// the request is posted to the path of its provider with its extra fields
 func (s *LoginSrvServer) AttemptLogin(ctx context.Context, request *LoginRequest) (*LoginReply, error) {
-	data := "username=" + request.Username + "&password=" + request.Password
-	return s.postLogin(&data, nil)
+	path, err := s.loginPath(request.Provider)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	for name, value := range request.Extra {
+		if name == "username" || name == "password" {
+			return nil, grpc.Errorf(codes.InvalidArgument, "extra field %q is reserved", name)
+		}
+		form.Set(name, value)
+	}
+	form.Set("username", request.Username)
+	form.Set("password", request.Password)
+
+	data := form.Encode()
+	body, err := s.requestAPI("POST", path, "jwt", &data)
+	if err != nil {
+		return nil, err
+	}
+	return s.referenceReply(ctx, s.newLoginReply(*body))
 }
 
 func (s *LoginSrvServer) postLogin(data *string, token *string) (*LoginReply, error) {
 	body, err := s.loginWithAPI("POST", "jwt", data, token)
 	if err == nil {
-		return &LoginReply{AccessToken: *body}, err
+		return s.newLoginReply(*body), err
 	}
 	return nil, err
 }
 
-func (s *LoginSrvServer) loginWithAPI(method string, contentType string, loginData *string, cookie *string) (*string, error) {
-	var reader io.Reader = nil
-	if loginData != nil {
-		reader = strings.NewReader(*loginData)
+// newLoginReply describes a token issued by loginsrv,
+// its claims are trusted as it comes straight from loginsrv
+func (s *LoginSrvServer) newLoginReply(token string) *LoginReply {
+	reply := &LoginReply{
+		AccessToken:        token,
+		TokenType:          "bearer",
+		RefreshesRemaining: -1,
 	}
-	req, err := http.NewRequest(method, *s.baseURL+"/login", reader)
-	req.Header.Add("Accept", "application/"+contentType)
-	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	claims, err := ParseUnverifiedClaims(token)
+	if err != nil {
+		return reply
+	}
+	reply.ExpiresAt = claims.Expiry
+	reply.Profile = claims.Profile()
+	if s.maxRefreshes >= 0 {
+		reply.RefreshesRemaining = int32(s.maxRefreshes - claims.Refreshes)
+		if reply.RefreshesRemaining < 0 {
+			reply.RefreshesRemaining = 0
+		}
+	}
+	return reply
+}
+
+func (s *LoginSrvServer) loginWithAPI(method string, contentType string, loginData *string, cookie *string) (*string, error) {
+	var cookies []*http.Cookie
 	if cookie != nil {
-		req.Header.Add("Cookie", "jwt_token="+*cookie)
+		cookies = append(cookies, &http.Cookie{Name: jwtCookieName, Value: *cookie})
+	}
+	return s.requestAPI(method, "/login", contentType, loginData, cookies...)
+}
+
+func (s *LoginSrvServer) requestAPI(method string, path string, contentType string, data *string, cookies ...*http.Cookie) (*string, error) {
+	var reader io.Reader = nil
+	if data != nil {
+		reader = strings.NewReader(*data)
 	}
+	req, err := http.NewRequest(method, *s.baseURL+path, reader)
 	if err != nil {
 		return nil, grpc.Errorf(codes.Unknown, "Unknown")
 	}
+	req.Header.Add("Accept", "application/"+contentType)
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
 
 	resp, err := s.apiClient.Do(req)
 	if err != nil {
@@ -127,11 +238,153 @@ func (s *LoginSrvServer) loginWithAPI(method string, contentType string, loginDa
 
 // RefreshToken refreshes the token sent through the context metadata
 func (s *LoginSrvServer) RefreshToken(ctx context.Context, request *RefreshRequest) (*LoginReply, error) {
-	oldToken := getTokenFromContext(ctx)
+	oldToken := s.tokenFromContext(ctx)
+	if oldToken == nil {
+		return nil, grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	reply, err := s.refreshToken(*oldToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.referenceReply(ctx, reply)
+}
+
+// refreshToken tells the watchers of the old token about the new one
+func (s *LoginSrvServer) refreshToken(oldToken string) (*LoginReply, error) {
+	reply, err := s.postLogin(nil, &oldToken)
+	if err != nil {
+		return nil, err
+	}
+
+	s.events.publishToken(oldToken, &SessionEvent{
+		Type:        SessionEvent_TOKEN_REFRESHED,
+		AccessToken: reply.AccessToken,
+		ExpiresAt:   reply.ExpiresAt,
+	})
+	return reply, nil
+}
+
+// Logout ends the session upstream and revokes the token sent through the context metadata
+// the reply header carries the cookies clearing a cookie based session
+func (s *LoginSrvServer) Logout(ctx context.Context, request *LogoutRequest) (*LogoutReply, error) {
+	oldToken := s.tokenFromContext(ctx)
 	if oldToken == nil {
 		return nil, grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
 	}
-	return s.postLogin(nil, oldToken)
+	// only tokens that validate are remembered as revoked
+	if _, err := s.validateToken(*oldToken); err != nil {
+		return nil, err
+	}
+
+	cookies, err := s.logoutWithAPI(*oldToken)
+	if err != nil {
+		return nil, err
+	}
+	s.revoked.revoke(*oldToken)
+	s.dropReference(ctx)
+	s.events.publishToken(*oldToken, &SessionEvent{
+		Type:    SessionEvent_REVOKED,
+		Message: "logged out",
+	})
+
+	// SetHeader only fails outside of an RPC, when the server is called directly
+	_ = grpc.SetHeader(ctx, md.MD{setCookieMetadataKey: cookies})
+	return &LogoutReply{}, nil
+}
+
+func (s *LoginSrvServer) logoutWithAPI(cookie string) ([]string, error) {
+	req, err := http.NewRequest("GET", *s.baseURL+"/login?logout=true", nil)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+	req.Header.Add("Cookie", jwtCookieName+"="+cookie)
+
+	resp, err := s.noRedirectClient().Do(req)
+	if err != nil {
+		return nil, grpc.Errorf(codes.Unknown, "Unknown")
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return nil, grpc.Errorf(codes.Unknown, string(body))
+	}
+
+	cookies := resp.Header["Set-Cookie"]
+	if len(cookies) == 0 {
+		cookies = []string{deleteJWTCookie().String()}
+	}
+	return cookies, nil
+}
+
+// noRedirectClient shares the api client settings but hands redirects back to the caller
+func (s *LoginSrvServer) noRedirectClient() *http.Client {
+	client := *s.apiClient
+	client.CheckRedirect = func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	return &client
+}
+
+func deleteJWTCookie() *http.Cookie {
+	return &http.Cookie{
+		Name:     jwtCookieName,
+		Value:    "delete",
+		Path:     "/",
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+	}
+}
+
+// tokenFromContext returns the token of the RPC unless it was revoked,
+// the handle of a reference token is resolved
+func (s *LoginSrvServer) tokenFromContext(ctx context.Context) *string {
+	presented := s.presentedToken(ctx)
+	if presented == nil {
+		return nil
+	}
+	token, err := s.resolveToken(ctx, *presented)
+	if err != nil || s.revoked.has(token) {
+		return nil
+	}
+	return &token
+}
+
+// presentedToken returns the token sent by the client, in the authorization or the cookie metadata
+func (s *LoginSrvServer) presentedToken(ctx context.Context) *string {
+	if cookie := s.cookieToken(ctx); cookie != "" {
+		return &cookie
+	}
+	return getTokenFromContext(ctx)
+}
+
+// cookieToken returns the token of the cookie metadata of an RPC without authorization metadata
+func (s *LoginSrvServer) cookieToken(ctx context.Context) string {
+	if s.cookieName == "" {
+		return ""
+	}
+	metadata, _ := md.FromIncomingContext(ctx)
+	if len(metadata.Get(AuthTokenMetadataKey)) > 0 {
+		return ""
+	}
+
+	// let net/http parse the cookie headers
+	request := &http.Request{Header: http.Header{"Cookie": metadata.Get("cookie")}}
+	cookie, err := request.Cookie(s.cookieName)
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+// httpCookieName is the cookie carrying the token of http requests,
+// the one of WithCookieToken or the jwt_token cookie of loginsrv
+func (s *LoginSrvServer) httpCookieName() string {
+	if s.cookieName == "" {
+		return jwtCookieName
+	}
+	return s.cookieName
 }
 
 func getTokenFromContext(ctx context.Context) *string {
@@ -154,42 +407,22 @@ func getTokenFromContext(ctx context.Context) *string {
 
 // GetProfile returns the user profile
 func (s *LoginSrvServer) GetProfile(ctx context.Context, profileRequest *ProfileRequest) (*Profile, error) {
-	oldToken := getTokenFromContext(ctx)
+	oldToken := s.tokenFromContext(ctx)
 	if oldToken == nil {
 		return nil, grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
 	}
-	jsonStr, err := s.loginWithAPI("GET", "json", nil, oldToken)
+	return s.fetchProfile(*oldToken)
+}
+
+func (s *LoginSrvServer) fetchProfile(token string) (*Profile, error) {
+	jsonStr, err := s.loginWithAPI("GET", "json", nil, &token)
 	if err != nil {
 		return nil, err
 	}
 
-	user := &userInfo{}
-	err = json.Unmarshal([]byte(*jsonStr), user)
+	user, err := parseClaims([]byte(*jsonStr))
 	if err != nil {
 		return nil, grpc.Errorf(codes.Internal, "Internal")
 	}
-	return &Profile{
-		Sub:       user.Sub,
-		Picture:   user.Picture,
-		Name:      user.Name,
-		Email:     user.Email,
-		Origin:    user.Origin,
-		Expiry:    user.Expiry,
-		Refreshes: int32(user.Refreshes),
-		Domain:    user.Domain,
-		Groups:    user.Groups,
-	}, nil
-}
-
-// Loginsrv returned profile type
-type userInfo struct {
-	Sub       string   `json:"sub"`
-	Picture   string   `json:"picture,omitempty"`
-	Name      string   `json:"name,omitempty"`
-	Email     string   `json:"email,omitempty"`
-	Origin    string   `json:"origin,omitempty"`
-	Expiry    int64    `json:"exp,omitempty"`
-	Refreshes int      `json:"refs,omitempty"`
-	Domain    string   `json:"domain,omitempty"`
-	Groups    []string `json:"groups,omitempty"`
+	return user.Profile(), nil
 }
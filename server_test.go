@@ -1,10 +1,13 @@
 package loginsrv_grpc
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"google.golang.org/grpc/codes"
 	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 )
 
 func TestAuthenticateWithoutTokenFails(t *testing.T) {
@@ -83,6 +86,76 @@ func TestAttemptLoginThenRefresh(t *testing.T) {
 	assertHasAccessToken(t, refreshReply)
 }
 
+func TestLogoutRevokesToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	token := obtainTokenOrFail(t, srv)
+	ctx := &contextWithAuthorizationStub{authToken: token}
+
+	if _, err := srv.Logout(ctx, &LogoutRequest{}); err != nil {
+		t.Fatal("Logout failed", err)
+	}
+	if upstream.logouts != 1 {
+		t.Error("Logout should be sent upstream")
+	}
+
+	if _, err := srv.Authenticate(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Error("Revoked token should not authenticate", err)
+	}
+	if _, err := srv.RefreshToken(ctx, &RefreshRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Revoked token should not refresh", err)
+	}
+}
+
+func TestLogoutRejectsInvalidToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	ctx := &contextWithAuthorizationStub{authToken: "garbage"}
+	if _, err := srv.Logout(ctx, &LogoutRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Logout of an invalid token should fail", err)
+	}
+	if upstream.logouts != 0 || srv.revoked.has("garbage") {
+		t.Error("Invalid token should neither be sent upstream nor revoked")
+	}
+}
+
+func TestLoginReplyDescribesToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithMaxRefreshes(3))
+
+	loginReply, err := srv.AttemptLogin(nil, &LoginRequest{Username: "bob", Password: "secret"})
+	if err != nil {
+		t.Fatal("Login failed", err)
+	}
+	if loginReply.TokenType != "bearer" || loginReply.GetProfile().GetSub() != "bob" {
+		t.Errorf("Unexpected reply %v", loginReply)
+	}
+	if loginReply.ExpiresAt <= time.Now().Unix() {
+		t.Error("Expiry should be in the future")
+	}
+	if loginReply.RefreshesRemaining != 3 {
+		t.Errorf("Expected 3 refreshes left but got %d", loginReply.RefreshesRemaining)
+	}
+
+	refreshCtx := &contextWithAuthorizationStub{authToken: loginReply.AccessToken}
+	refreshReply, err := srv.RefreshToken(refreshCtx, &RefreshRequest{})
+	if err != nil {
+		t.Fatal("Refresh failed", err)
+	}
+	if refreshReply.RefreshesRemaining != 2 {
+		t.Errorf("Expected 2 refreshes left but got %d", refreshReply.RefreshesRemaining)
+	}
+
+	srv = NewLoginSrvServer(upstream.URL)
+	if loginReply, _ = srv.AttemptLogin(nil, &LoginRequest{Username: "bob", Password: "secret"}); loginReply.RefreshesRemaining != -1 {
+		t.Error("Refreshes left should be unknown without WithMaxRefreshes")
+	}
+}
+
 func obtainTokenOrFail(t *testing.T, srv *LoginSrvServer) string {
 	loginReply, err := srv.AttemptLogin(nil, &LoginRequest{
 		Username: "bob",
@@ -125,3 +198,35 @@ func (m *contextWithAuthorizationStub) Value(key interface{}) interface{} {
 		AuthTokenMetadataKey: "bearer " + m.authToken,
 	})
 }
+
+func TestAuthenticateWithCookieToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithCookieToken("session"))
+	token := obtainTokenOrFail(t, srv)
+
+	withCookie := func(pairs ...string) context.Context {
+		return md.NewIncomingContext(context.Background(), md.Pairs(pairs...))
+	}
+
+	ctx := withCookie("cookie", "theme=dark; session="+token)
+	if _, err := srv.Authenticate(ctx); err != nil {
+		t.Error("Cookie token should authenticate", err)
+	}
+	if profile, err := srv.GetProfile(ctx, &ProfileRequest{}); err != nil || profile.Sub != "bob" {
+		t.Error("Cookie token should be used by the RPCs", err)
+	}
+
+	ctx = withCookie(AuthTokenMetadataKey, "bearer garbage", "cookie", "session="+token)
+	if _, err := srv.Authenticate(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Error("Authorization metadata should take precedence over the cookie", err)
+	}
+
+	ctx = withCookie("cookie", jwtCookieName+"="+token)
+	if _, err := srv.Authenticate(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Error("Only the configured cookie should be read", err)
+	}
+	if _, err := NewLoginSrvServer(upstream.URL).Authenticate(withCookie("cookie", jwtCookieName+"="+token)); status.Code(err) != codes.Unauthenticated {
+		t.Error("Cookies should be ignored without WithCookieToken", err)
+	}
+}
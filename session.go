@@ -0,0 +1,294 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Session keeps the token of a logged in user and runs the
+// login, refresh and profile flow on top of an AuthClient
+type Session struct {
+	client AuthClient
+
+	mu        sync.RWMutex
+	refreshMu sync.Mutex
+	token     *string
+	expiry    time.Time
+	profile   *Profile
+
+	refreshBefore time.Duration
+
+	onTokenChanged   func(token *string)
+	onSessionExpired func(err error)
+}
+
+// SessionOption allows functional configuration for the Session
+type SessionOption func(*Session)
+
+// WithTokenChangedHandler is called after every login, refresh and logout
+// the token is nil once the session is over
+func WithTokenChangedHandler(handler func(token *string)) SessionOption {
+	return func(s *Session) {
+		s.onTokenChanged = handler
+	}
+}
+
+// WithSessionExpiredHandler is called when the server rejects the session token
+func WithSessionExpiredHandler(handler func(err error)) SessionOption {
+	return func(s *Session) {
+		s.onSessionExpired = handler
+	}
+}
+
+// WithRefreshBefore sets how long before its expiry the token is refreshed
+// by the Session interceptors, the default is one minute
+func WithRefreshBefore(d time.Duration) SessionOption {
+	return func(s *Session) {
+		s.refreshBefore = d
+	}
+}
+
+// NewSession creates a Session using the client for the Auth RPCs
+func NewSession(client AuthClient, options ...SessionOption) *Session {
+	s := &Session{
+		client:        client,
+		refreshBefore: time.Minute,
+	}
+
+	for i := range options {
+		options[i](s)
+	}
+	return s
+}
+
+// Login obtains a token for the given credentials
+func (s *Session) Login(ctx context.Context, username string, password string) error {
+	reply, err := s.client.AttemptLogin(ctx, &LoginRequest{
+		Username: username,
+		Password: password,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.setToken(&reply.AccessToken, replyExpiry(reply))
+	return nil
+}
+
+// Refresh exchanges the current token for a new one
+func (s *Session) Refresh(ctx context.Context) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	return s.refresh(ctx)
+}
+
+// refresh must be called with refreshMu held
+func (s *Session) refresh(ctx context.Context) error {
+	token := s.Token()
+	if token == nil {
+		return status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+
+	reply, err := s.client.RefreshToken(withOutgoingToken(ctx, *token), &RefreshRequest{})
+	if err != nil {
+		s.expireOn(*token, err)
+		return err
+	}
+
+	s.setToken(&reply.AccessToken, replyExpiry(reply))
+	return nil
+}
+
+// Logout ends the session on the server then forgets the token and the cached profile
+// the token is forgotten even when the server rejects it
+func (s *Session) Logout(ctx context.Context) error {
+	token := s.Token()
+	if token == nil {
+		return nil
+	}
+
+	_, err := s.client.Logout(withOutgoingToken(ctx, *token), &LogoutRequest{})
+	s.setToken(nil, time.Time{})
+	if status.Code(err) == codes.Unauthenticated {
+		return nil
+	}
+	return err
+}
+
+// Profile returns the profile of the logged in user
+// it is loaded once per token
+func (s *Session) Profile(ctx context.Context) (*Profile, error) {
+	s.mu.RLock()
+	token, profile := s.token, s.profile
+	s.mu.RUnlock()
+
+	if token == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	if profile != nil {
+		return profile, nil
+	}
+
+	profile, err := s.client.GetProfile(withOutgoingToken(ctx, *token), &ProfileRequest{})
+	if err != nil {
+		s.expireOn(*token, err)
+		return nil, err
+	}
+
+	s.mu.Lock()
+	if s.token == token {
+		s.profile = profile
+	}
+	s.mu.Unlock()
+	return profile, nil
+}
+
+// Token returns the current token or nil when logged out
+// it can be used as a TokenGetter
+func (s *Session) Token() *string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+// ContextToken returns the session token, refreshing it first when it is about to expire
+// it can be used as a ContextTokenGetter
+func (s *Session) ContextToken(ctx context.Context, method string) (string, error) {
+	s.mu.RLock()
+	token, expiry := s.token, s.expiry
+	s.mu.RUnlock()
+	if token == nil {
+		return "", nil
+	}
+
+	if expiry.IsZero() || time.Until(expiry) > s.refreshBefore {
+		return *token, nil
+	}
+
+	if err := s.refreshExpiring(ctx, token); err != nil && !time.Now().Before(expiry) {
+		return "", err
+	}
+	if token = s.Token(); token == nil {
+		return "", nil
+	}
+	return *token, nil
+}
+
+// DialOptions attaches the session token to every RPC of the connection
+// and ends the session when the server answers with Unauthenticated
+func (s *Session) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(
+			NewClientContextTokenInterceptor(s.ContextToken),
+			s.unaryExpiryInterceptor,
+		),
+		grpc.WithChainStreamInterceptor(
+			NewClientContextStreamTokenInterceptor(s.ContextToken),
+			s.streamExpiryInterceptor,
+		),
+	}
+}
+
+// refreshExpiring refreshes the token unless a concurrent call already replaced it
+func (s *Session) refreshExpiring(ctx context.Context, token *string) error {
+	s.refreshMu.Lock()
+	defer s.refreshMu.Unlock()
+
+	if s.Token() != token {
+		return nil
+	}
+	return s.refresh(ctx)
+}
+
+func (s *Session) unaryExpiryInterceptor(
+	ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if status.Code(err) == codes.Unauthenticated {
+		s.expireOn(sentToken(ctx), err)
+	}
+	return err
+}
+
+func (s *Session) streamExpiryInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+	stream, err := streamer(ctx, desc, cc, method, opts...)
+	if status.Code(err) == codes.Unauthenticated {
+		s.expireOn(sentToken(ctx), err)
+	}
+	return stream, err
+}
+
+func (s *Session) setToken(token *string, expiry time.Time) {
+	s.mu.Lock()
+	s.token = token
+	s.expiry = expiry
+	s.profile = nil
+	s.mu.Unlock()
+
+	if s.onTokenChanged != nil {
+		s.onTokenChanged(token)
+	}
+}
+
+// replyExpiry prefers the expiry of the reply, the token may be an opaque handle
+func replyExpiry(reply *LoginReply) time.Time {
+	if reply.ExpiresAt != 0 {
+		return time.Unix(reply.ExpiresAt, 0)
+	}
+	return tokenExpiry(reply.AccessToken)
+}
+
+// expireOn ends the session when err means the token the call was sent with is no longer accepted,
+// a late failure of a token which was replaced since leaves the session alone
+func (s *Session) expireOn(token string, err error) {
+	code := status.Code(err)
+	if code != codes.Unauthenticated && code != codes.PermissionDenied {
+		return
+	}
+
+	s.mu.Lock()
+	if s.token == nil || *s.token != token {
+		s.mu.Unlock()
+		return
+	}
+	s.token, s.expiry, s.profile = nil, time.Time{}, nil
+	s.mu.Unlock()
+
+	if s.onTokenChanged != nil {
+		s.onTokenChanged(nil)
+	}
+	if s.onSessionExpired != nil {
+		s.onSessionExpired(err)
+	}
+}
+
+// sentToken returns the bearer token of the outgoing metadata of a call
+func sentToken(ctx context.Context) string {
+	metadata, _ := md.FromOutgoingContext(ctx)
+	values := metadata.Get(AuthTokenMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(values[0], "bearer ")
+}
@@ -0,0 +1,97 @@
+package loginsrv_grpc
+
+import (
+	"sync"
+)
+
+// sessionEventBuffer is the number of events a slow watcher can lag behind
+const sessionEventBuffer = 16
+
+// sessionBus delivers session events to the watchers of a token or a subject
+type sessionBus struct {
+	mu       sync.Mutex
+	watchers map[*sessionWatcher]struct{}
+}
+
+// sessionWatcher receives the events of the session it follows
+type sessionWatcher struct {
+	sub    string
+	token  string
+	events chan *SessionEvent
+}
+
+func newSessionBus() *sessionBus {
+	return &sessionBus{watchers: map[*sessionWatcher]struct{}{}}
+}
+
+func (b *sessionBus) subscribe(sub string, token string) *sessionWatcher {
+	w := &sessionWatcher{
+		sub:    sub,
+		token:  token,
+		events: make(chan *SessionEvent, sessionEventBuffer),
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.watchers[w] = struct{}{}
+	return w
+}
+
+func (b *sessionBus) unsubscribe(w *sessionWatcher) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.watchers, w)
+}
+
+// publishToken sends the event to the watchers of the token,
+// a refreshed token becomes the one they follow
+func (b *sessionBus) publishToken(token string, event *SessionEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for w := range b.watchers {
+		if w.token != token {
+			continue
+		}
+		if event.Type == SessionEvent_TOKEN_REFRESHED {
+			w.token = event.AccessToken
+		}
+		w.deliver(event)
+	}
+}
+
+// publishSubject sends the event to the watchers of every session of the subject
+// and returns the tokens they follow
+func (b *sessionBus) publishSubject(sub string, event *SessionEvent) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tokens := []string{}
+	for w := range b.watchers {
+		if w.sub == sub {
+			tokens = append(tokens, w.token)
+			w.deliver(event)
+		}
+	}
+	return tokens
+}
+
+// deliver drops the event when the watcher is too far behind,
+// events ending the session make room by dropping the oldest one instead
+// it must be called with the bus lock held, the bus being the only sender
+func (w *sessionWatcher) deliver(event *SessionEvent) {
+	select {
+	case w.events <- event:
+		return
+	default:
+	}
+	if event.Type != SessionEvent_REVOKED && event.Type != SessionEvent_FORCED_LOGOUT {
+		return
+	}
+
+	select {
+	case <-w.events:
+	default:
+	}
+	w.events <- event
+}
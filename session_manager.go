@@ -0,0 +1,210 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// SessionManager holds independent sessions keyed by an identity,
+// such as a username or a tenant, each refreshing its own token
+type SessionManager struct {
+	client  AuthClient
+	options []SessionOption
+
+	mu       sync.RWMutex
+	sessions map[string]*Session
+}
+
+// NewSessionManager creates a SessionManager, the options apply to every session
+func NewSessionManager(client AuthClient, options ...SessionOption) *SessionManager {
+	return &SessionManager{
+		client:   client,
+		options:  options,
+		sessions: map[string]*Session{},
+	}
+}
+
+// Login logs the identity in with the given credentials,
+// the identity only gets a session once the login succeeded
+func (m *SessionManager) Login(ctx context.Context, identity string, username string, password string) error {
+	m.mu.RLock()
+	session := m.sessions[identity]
+	m.mu.RUnlock()
+	if session == nil {
+		session = NewSession(m.client, m.options...)
+	}
+
+	if err := session.Login(ctx, username, password); err != nil {
+		m.dropEnded(identity, session)
+		return err
+	}
+
+	m.mu.Lock()
+	m.sessions[identity] = session
+	m.mu.Unlock()
+	return nil
+}
+
+// Logout ends the session of the identity
+func (m *SessionManager) Logout(ctx context.Context, identity string) error {
+	m.mu.Lock()
+	session := m.sessions[identity]
+	delete(m.sessions, identity)
+	m.mu.Unlock()
+
+	if session == nil {
+		return nil
+	}
+	return session.Logout(ctx)
+}
+
+// Session returns the session of the identity or nil when it is not logged in,
+// sessions which expired are dropped
+func (m *SessionManager) Session(identity string) *Session {
+	m.mu.RLock()
+	session := m.sessions[identity]
+	m.mu.RUnlock()
+
+	if session == nil || session.Token() == nil {
+		m.dropEnded(identity, session)
+		return nil
+	}
+	return session
+}
+
+// Identities returns the identities having a session which did not expire
+func (m *SessionManager) Identities() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	identities := make([]string, 0, len(m.sessions))
+	for identity, session := range m.sessions {
+		if session.Token() != nil {
+			identities = append(identities, identity)
+		}
+	}
+	return identities
+}
+
+// DialOptions attaches the token of the identity selected with WithIdentity
+// or the Identity call option to every RPC of the connection
+func (m *SessionManager) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(m.unaryInterceptor),
+		grpc.WithChainStreamInterceptor(m.streamInterceptor),
+	}
+}
+
+// dropEnded removes the session of the identity when it has no token,
+// unless it was replaced in the meantime
+func (m *SessionManager) dropEnded(identity string, session *Session) {
+	if session == nil || session.Token() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions[identity] == session {
+		delete(m.sessions, identity)
+	}
+}
+
+// sessionOf returns the session selected for the RPC, an identity whose session expired has none
+func (m *SessionManager) sessionOf(ctx context.Context, opts []grpc.CallOption) (*Session, error) {
+	identity, ok := identityFromCallOptions(opts)
+	if !ok {
+		identity, ok = IdentityFromContext(ctx)
+	}
+	if !ok {
+		return nil, status.Errorf(codes.Unauthenticated, "no identity selected")
+	}
+
+	session := m.Session(identity)
+	if session == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "no session for identity %q", identity)
+	}
+	return session, nil
+}
+
+func (m *SessionManager) unaryInterceptor(
+	ctx context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	cc *grpc.ClientConn,
+	invoker grpc.UnaryInvoker,
+	opts ...grpc.CallOption) error {
+
+	if hasOutgoingToken(ctx) {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	session, err := m.sessionOf(ctx, opts)
+	if err != nil {
+		return err
+	}
+	ctx, err = withTokenFrom(ctx, method, session.ContextToken)
+	if err != nil {
+		return err
+	}
+	return session.unaryExpiryInterceptor(ctx, method, req, reply, cc, invoker, opts...)
+}
+
+func (m *SessionManager) streamInterceptor(
+	ctx context.Context,
+	desc *grpc.StreamDesc,
+	cc *grpc.ClientConn,
+	method string,
+	streamer grpc.Streamer,
+	opts ...grpc.CallOption) (grpc.ClientStream, error) {
+
+	if hasOutgoingToken(ctx) {
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+	session, err := m.sessionOf(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx, err = withTokenFrom(ctx, method, session.ContextToken)
+	if err != nil {
+		return nil, err
+	}
+	return session.streamExpiryInterceptor(ctx, desc, cc, method, streamer, opts...)
+}
+
+type identityKey struct{}
+
+// WithIdentity selects the SessionManager identity used by the RPCs made with ctx
+func WithIdentity(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// IdentityFromContext returns the identity selected with WithIdentity
+func IdentityFromContext(ctx context.Context) (string, bool) {
+	identity, ok := ctx.Value(identityKey{}).(string)
+	return identity, ok
+}
+
+// IdentityCallOption selects the SessionManager identity of a single RPC
+// it takes precedence over WithIdentity
+type IdentityCallOption struct {
+	grpc.EmptyCallOption
+	Identity string
+}
+
+// Identity selects the SessionManager identity of a single RPC
+func Identity(identity string) IdentityCallOption {
+	return IdentityCallOption{Identity: identity}
+}
+
+func identityFromCallOptions(opts []grpc.CallOption) (string, bool) {
+	for _, opt := range opts {
+		if o, ok := opt.(IdentityCallOption); ok {
+			return o.Identity, true
+		}
+	}
+	return "", false
+}
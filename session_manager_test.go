@@ -0,0 +1,104 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestSessionManagerSelectsIdentityPerCall(t *testing.T) {
+	client := &authClientStub{loginTokens: map[string]string{"bob": "bob-token", "alice": "alice-token"}}
+	manager := NewSessionManager(client)
+	for _, user := range []string{"bob", "alice"} {
+		if err := manager.Login(context.Background(), user, user, "secret"); err != nil {
+			t.Fatal("Login failed", err)
+		}
+	}
+
+	var sent string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		sent = outgoingToken(ctx)
+		return nil
+	}
+
+	ctx := WithIdentity(context.Background(), "bob")
+	if err := manager.unaryInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker); err != nil {
+		t.Fatal(err)
+	}
+	if sent != "bob-token" {
+		t.Error("Expected the token of bob but got " + sent)
+	}
+
+	if err := manager.unaryInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker, Identity("alice")); err != nil {
+		t.Fatal(err)
+	}
+	if sent != "alice-token" {
+		t.Error("Call option should take precedence but got " + sent)
+	}
+}
+
+func TestSessionManagerRejectsUnknownIdentity(t *testing.T) {
+	manager := NewSessionManager(&authClientStub{})
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return nil
+	}
+
+	for _, ctx := range []context.Context{context.Background(), WithIdentity(context.Background(), "carol")} {
+		err := manager.unaryInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker)
+		if status.Code(err) != codes.Unauthenticated {
+			t.Error("Expected Unauthenticated but got", err)
+		}
+	}
+}
+
+func TestSessionManagerSessionsAreIndependent(t *testing.T) {
+	client := &authClientStub{loginTokens: map[string]string{"bob": "bob-token", "alice": "alice-token"}}
+	manager := NewSessionManager(client)
+	manager.Login(context.Background(), "bob", "bob", "secret")
+	manager.Login(context.Background(), "alice", "alice", "secret")
+
+	if err := manager.Logout(context.Background(), "bob"); err != nil {
+		t.Fatal("Logout failed", err)
+	}
+	if manager.Session("bob") != nil {
+		t.Error("Session of bob should be removed")
+	}
+	if token := manager.Session("alice").Token(); token == nil || *token != "alice-token" {
+		t.Error("Session of alice should be kept")
+	}
+}
+
+func TestSessionManagerKeepsOnlyLoggedInSessions(t *testing.T) {
+	client := &authClientStub{loginErr: status.Error(codes.Unauthenticated, "bad credentials")}
+	manager := NewSessionManager(client)
+	if err := manager.Login(context.Background(), "tenant-a", "bob", "wrong"); err == nil {
+		t.Fatal("Login should fail")
+	}
+	if manager.Session("tenant-a") != nil || len(manager.Identities()) != 0 {
+		t.Error("Failed login should not leave a session")
+	}
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		t.Error("RPC should not be sent without a token")
+		return nil
+	}
+	ctx := WithIdentity(context.Background(), "tenant-a")
+	if err := manager.unaryInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker); status.Code(err) != codes.Unauthenticated {
+		t.Error("Expected Unauthenticated but got", err)
+	}
+
+	client.loginErr = nil
+	client.refreshErr = status.Error(codes.Unauthenticated, "expired")
+	if err := manager.Login(context.Background(), "tenant-a", "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+	manager.Session("tenant-a").Refresh(context.Background())
+	if manager.Session("tenant-a") != nil || len(manager.Identities()) != 0 {
+		t.Error("Expired session should be dropped")
+	}
+	if err := manager.unaryInterceptor(ctx, "/svc/Method", nil, nil, nil, invoker); status.Code(err) != codes.Unauthenticated {
+		t.Error("Expected Unauthenticated but got", err)
+	}
+}
@@ -0,0 +1,204 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestSessionLoginRefreshAndProfile(t *testing.T) {
+	client := &authClientStub{}
+	var changes []string
+	session := NewSession(client, WithTokenChangedHandler(func(token *string) {
+		if token != nil {
+			changes = append(changes, *token)
+		}
+	}))
+
+	if err := session.Login(context.Background(), "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+	if err := session.Refresh(context.Background()); err != nil {
+		t.Fatal("Refresh failed", err)
+	}
+	if client.lastToken != "token-1" {
+		t.Error("Refresh should send the login token but sent " + client.lastToken)
+	}
+
+	for i := 0; i < 2; i++ {
+		profile, err := session.Profile(context.Background())
+		if err != nil {
+			t.Fatal("Profile failed", err)
+		}
+		if profile.Sub != "bob" {
+			t.Error("Expected 'bob' profile but got " + profile.Sub)
+		}
+	}
+	if client.profileCalls != 1 {
+		t.Errorf("Profile should be cached but was loaded %d times", client.profileCalls)
+	}
+
+	if len(changes) != 2 || changes[1] != "token-2" {
+		t.Errorf("Unexpected token changes %v", changes)
+	}
+
+	if err := session.Logout(context.Background()); err != nil {
+		t.Fatal("Logout failed", err)
+	}
+	if client.logouts != 1 || client.lastToken != "token-2" {
+		t.Error("Logout should be sent with the current token")
+	}
+	if session.Token() != nil {
+		t.Error("Token should be dropped after logout")
+	}
+}
+
+func TestSessionExpiresOnUnauthenticated(t *testing.T) {
+	client := &authClientStub{refreshErr: status.Error(codes.Unauthenticated, "expired")}
+	expired := false
+	session := NewSession(client, WithSessionExpiredHandler(func(err error) {
+		expired = true
+	}))
+
+	if err := session.Login(context.Background(), "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+	if err := session.Refresh(context.Background()); err == nil {
+		t.Error("Refresh should fail")
+	}
+
+	if !expired {
+		t.Error("Session expired handler should be called")
+	}
+	if session.Token() != nil {
+		t.Error("Token should be dropped after expiry")
+	}
+}
+
+func TestSessionRefreshesExpiringToken(t *testing.T) {
+	client := &authClientStub{
+		loginToken: unsignedTestToken(map[string]interface{}{"sub": "bob", "exp": time.Now().Add(30 * time.Second).Unix()}),
+	}
+	session := NewSession(client, WithRefreshBefore(time.Minute))
+	if err := session.Login(context.Background(), "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+
+	token, err := session.ContextToken(context.Background(), "/svc/Method")
+	if err != nil {
+		t.Fatal("ContextToken failed", err)
+	}
+	if token != "token-2" {
+		t.Error("Expiring token should be refreshed but got " + token)
+	}
+}
+
+func TestSessionRefreshesExpiringHandle(t *testing.T) {
+	client := &authClientStub{loginToken: "handle-1", loginExpiresAt: time.Now().Add(30 * time.Second).Unix()}
+	session := NewSession(client, WithRefreshBefore(time.Minute))
+	if err := session.Login(context.Background(), "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+
+	token, err := session.ContextToken(context.Background(), "/svc/Method")
+	if err != nil {
+		t.Fatal("ContextToken failed", err)
+	}
+	if token != "token-2" {
+		t.Error("Opaque token should be refreshed from the reply expiry but got " + token)
+	}
+}
+
+func TestSessionIgnoresLateFailureOfReplacedToken(t *testing.T) {
+	client := &authClientStub{}
+	expired := false
+	session := NewSession(client, WithSessionExpiredHandler(func(err error) {
+		expired = true
+	}))
+	if err := session.Login(context.Background(), "bob", "secret"); err != nil {
+		t.Fatal("Login failed", err)
+	}
+	if err := session.Refresh(context.Background()); err != nil {
+		t.Fatal("Refresh failed", err)
+	}
+
+	failing := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		return status.Error(codes.Unauthenticated, "expired")
+	}
+	stale := withOutgoingToken(context.Background(), "token-1")
+	session.unaryExpiryInterceptor(stale, "/svc/Method", nil, nil, nil, failing)
+	if token := session.Token(); token == nil || *token != "token-2" || expired {
+		t.Error("Failure of the replaced token should not end the session")
+	}
+
+	current := withOutgoingToken(context.Background(), "token-2")
+	session.unaryExpiryInterceptor(current, "/svc/Method", nil, nil, nil, failing)
+	if session.Token() != nil || !expired {
+		t.Error("Failure of the current token should end the session")
+	}
+}
+
+type authClientStub struct {
+	AuthClient
+	logins         int
+	logouts        int
+	profileCalls   int
+	lastToken      string
+	loginToken     string
+	loginExpiresAt int64
+	loginTokens    map[string]string
+	loginErr       error
+	refreshErr     error
+	refreshToken   string
+}
+
+func (c *authClientStub) AttemptLogin(ctx context.Context, in *LoginRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	c.logins++
+	if c.loginErr != nil {
+		return nil, c.loginErr
+	}
+	if token, ok := c.loginTokens[in.Username]; ok {
+		return &LoginReply{AccessToken: token}, nil
+	}
+	if c.loginToken != "" {
+		return &LoginReply{AccessToken: c.loginToken, ExpiresAt: c.loginExpiresAt}, nil
+	}
+	return &LoginReply{AccessToken: "token-1"}, nil
+}
+
+func (c *authClientStub) RefreshToken(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*LoginReply, error) {
+	c.lastToken = outgoingToken(ctx)
+	if c.refreshErr != nil {
+		return nil, c.refreshErr
+	}
+	if c.refreshToken != "" {
+		return &LoginReply{AccessToken: c.refreshToken}, nil
+	}
+	return &LoginReply{AccessToken: "token-2"}, nil
+}
+
+func (c *authClientStub) GetProfile(ctx context.Context, in *ProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	c.profileCalls++
+	c.lastToken = outgoingToken(ctx)
+	return &Profile{Sub: "bob"}, nil
+}
+
+func (c *authClientStub) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutReply, error) {
+	c.logouts++
+	c.lastToken = outgoingToken(ctx)
+	return &LogoutReply{}, nil
+}
+
+func outgoingToken(ctx context.Context) string {
+	metadata, _ := md.FromOutgoingContext(ctx)
+	values := metadata.Get(AuthTokenMetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0][len("bearer "):]
+}
@@ -0,0 +1,168 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// TokenReviewResource is the resource given to the authorizer for kubernetes token reviews
+	TokenReviewResource = "authentication.k8s.io/tokenreviews"
+
+	// tokenReviewDomainKey is the user extra carrying the loginsrv domain
+	tokenReviewDomainKey = "loginsrv/domain"
+)
+
+// tokenReview is the authentication.k8s.io TokenReview object,
+// v1 and v1beta1 share the fields used by the webhook
+type tokenReview struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Spec       tokenReviewSpec   `json:"spec"`
+	Status     tokenReviewStatus `json:"status"`
+}
+
+type tokenReviewSpec struct {
+	Token     string   `json:"token,omitempty"`
+	Audiences []string `json:"audiences,omitempty"`
+}
+
+type tokenReviewStatus struct {
+	Authenticated bool             `json:"authenticated"`
+	User          *tokenReviewUser `json:"user,omitempty"`
+	Audiences     []string         `json:"audiences,omitempty"`
+	Error         string           `json:"error,omitempty"`
+}
+
+type tokenReviewUser struct {
+	Username string              `json:"username"`
+	UID      string              `json:"uid,omitempty"`
+	Groups   []string            `json:"groups,omitempty"`
+	Extra    map[string][]string `json:"extra,omitempty"`
+}
+
+// TokenReviewHandler implements the kubernetes webhook token authentication,
+// tokens are validated and authorized like in Authenticate
+// reviews naming audiences only succeed for the audiences of the token aud claim or of the handler
+type TokenReviewHandler struct {
+	srv       *LoginSrvServer
+	audiences []string
+}
+
+// TokenReviewOption allows functional configuration for the TokenReviewHandler
+type TokenReviewOption func(*TokenReviewHandler)
+
+// WithTokenReviewAudiences accepts every valid token for the audiences,
+// such as the api server audience of a cluster
+func WithTokenReviewAudiences(audiences ...string) TokenReviewOption {
+	return func(h *TokenReviewHandler) {
+		h.audiences = append(h.audiences, audiences...)
+	}
+}
+
+// NewTokenReviewHandler creates the handler to set as authentication-token-webhook of a cluster
+func NewTokenReviewHandler(srv *LoginSrvServer, options ...TokenReviewOption) *TokenReviewHandler {
+	h := &TokenReviewHandler{srv: srv}
+
+	for i := range options {
+		options[i](h)
+	}
+	return h
+}
+
+func (h *TokenReviewHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		w.Header().Set("Allow", "POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	review := &tokenReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Kind != "TokenReview" {
+		http.Error(w, "invalid TokenReview", http.StatusBadRequest)
+		return
+	}
+
+	profile, err := h.srv.authorize(r.Context(), review.Spec.Token, TokenReviewResource)
+	switch status.Code(err) {
+	case codes.OK:
+		audiences := h.matchAudiences(r.Context(), review.Spec)
+		if len(review.Spec.Audiences) > 0 && len(audiences) == 0 {
+			review.Status = tokenReviewStatus{Error: "token is not valid for the requested audiences"}
+			break
+		}
+		review.Status = tokenReviewStatus{
+			Authenticated: true,
+			User:          newTokenReviewUser(profile),
+			Audiences:     audiences,
+		}
+	case codes.Unauthenticated, codes.PermissionDenied:
+		review.Status = tokenReviewStatus{Error: status.Convert(err).Message()}
+	default:
+		// kubernetes retries reviews failing with an error status
+		http.Error(w, status.Convert(err).Message(), httpStatusFromCode(status.Code(err)))
+		return
+	}
+
+	// the token is not echoed back
+	review.Spec = tokenReviewSpec{}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// matchAudiences returns the requested audiences accepted by the handler or by the token
+func (h *TokenReviewHandler) matchAudiences(ctx context.Context, spec tokenReviewSpec) []string {
+	accepted := map[string]bool{}
+	for _, audience := range h.audiences {
+		accepted[audience] = true
+	}
+	// the token was just validated, its claims can be trusted
+	if token, err := h.srv.resolveToken(ctx, spec.Token); err == nil {
+		if claims, err := ParseUnverifiedClaims(token); err == nil {
+			for _, audience := range claimAudiences(claims) {
+				accepted[audience] = true
+			}
+		}
+	}
+
+	var audiences []string
+	for _, audience := range spec.Audiences {
+		if accepted[audience] {
+			audiences = append(audiences, audience)
+		}
+	}
+	return audiences
+}
+
+// claimAudiences returns the aud claim, a string or a list of strings
+func claimAudiences(claims *Claims) []string {
+	switch aud := claims.Extra["aud"].(type) {
+	case string:
+		return []string{aud}
+	case []interface{}:
+		audiences := []string{}
+		for _, value := range aud {
+			if audience, ok := value.(string); ok {
+				audiences = append(audiences, audience)
+			}
+		}
+		return audiences
+	}
+	return nil
+}
+
+func newTokenReviewUser(profile *Profile) *tokenReviewUser {
+	user := &tokenReviewUser{
+		Username: profile.Sub,
+		UID:      profile.Sub,
+		Groups:   profile.Groups,
+	}
+	if profile.Domain != "" {
+		user.Extra = map[string][]string{tokenReviewDomainKey: {profile.Domain}}
+	}
+	return user
+}
@@ -0,0 +1,105 @@
+package loginsrv_grpc
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var tokenReviewSecret = []byte("tokenreview-secret")
+
+func readJSONFixture(t *testing.T, name string) (raw []byte, value interface{}) {
+	raw, err := ioutil.ReadFile(filepath.Join("testdata", "tokenreview", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		t.Fatal(err)
+	}
+	return raw, value
+}
+
+func TestTokenReviewFixtures(t *testing.T) {
+	srv := NewLoginSrvServer("http://localhost:0", WithJWTSecret(tokenReviewSecret))
+	denying := NewLoginSrvServer("http://localhost:0", WithJWTSecret(tokenReviewSecret), WithAuthorizer(RequireGroups("admin")))
+
+	for _, tc := range []struct {
+		srv      *LoginSrvServer
+		request  string
+		response string
+	}{
+		{srv, "valid_request.json", "valid_response.json"},
+		{srv, "invalid_request.json", "invalid_response.json"},
+		{denying, "valid_request.json", "denied_response.json"},
+	} {
+		request, _ := readJSONFixture(t, tc.request)
+		_, expected := readJSONFixture(t, tc.response)
+
+		w := httptest.NewRecorder()
+		NewTokenReviewHandler(tc.srv, WithTokenReviewAudiences("https://kubernetes.default.svc")).ServeHTTP(w, httptest.NewRequest("POST", "/", bytes.NewReader(request)))
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s should be reviewed but got %d %s", tc.request, w.Code, w.Body)
+		}
+
+		var actual interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &actual); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("%s should be answered with %s but got %s", tc.request, tc.response, w.Body)
+		}
+	}
+}
+
+func TestTokenReviewChecksAudiences(t *testing.T) {
+	h := NewTokenReviewHandler(NewLoginSrvServer("http://localhost:0", WithJWTSecret(tokenReviewSecret)))
+	review := func(aud interface{}, audiences ...string) tokenReviewStatus {
+		claims := map[string]interface{}{"sub": "bob", "exp": 4102444800}
+		if aud != nil {
+			claims["aud"] = aud
+		}
+		body, _ := json.Marshal(&tokenReview{
+			Kind: "TokenReview",
+			Spec: tokenReviewSpec{Token: signedTestToken(tokenReviewSecret, claims), Audiences: audiences},
+		})
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/", bytes.NewReader(body)))
+		result := &tokenReview{}
+		json.Unmarshal(w.Body.Bytes(), result)
+		return result.Status
+	}
+
+	if status := review(nil, "https://kubernetes.default.svc"); status.Authenticated || status.Error == "" {
+		t.Errorf("Token without audience should not be valid for the cluster but got %v", status)
+	}
+	if status := review([]string{"vault", "cluster"}, "cluster", "other"); !status.Authenticated || !reflect.DeepEqual(status.Audiences, []string{"cluster"}) {
+		t.Errorf("Audiences should be those of the aud claim but got %v", status)
+	}
+	if status := review(nil); !status.Authenticated || len(status.Audiences) != 0 {
+		t.Errorf("Review without audiences should succeed but got %v", status)
+	}
+}
+
+func TestTokenReviewRejectsMalformedRequests(t *testing.T) {
+	h := NewTokenReviewHandler(NewLoginSrvServer("http://localhost:0", WithJWTSecret(tokenReviewSecret)))
+
+	for _, tc := range []struct {
+		method, body string
+		status       int
+	}{
+		{"GET", "", http.StatusMethodNotAllowed},
+		{"POST", "not json", http.StatusBadRequest},
+		{"POST", `{"kind":"SubjectAccessReview"}`, http.StatusBadRequest},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tc.method, "/", bytes.NewReader([]byte(tc.body))))
+		if w.Code != tc.status {
+			t.Errorf("%s %q should answer %d but got %d", tc.method, tc.body, tc.status, w.Code)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package loginsrvv1
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	loginsrv_grpc "github.com/motia/loginsrv-grpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// legacyServerStub answers like a LoginSrvServer for the user bob
+type legacyServerStub struct {
+	loginsrv_grpc.UnimplementedAuthServer
+}
+
+func (s *legacyServerStub) AuthFuncOverride(ctx context.Context, fullMethodName string) (context.Context, error) {
+	return ctx, nil
+}
+
+func (s *legacyServerStub) AttemptLogin(ctx context.Context, request *loginsrv_grpc.LoginRequest) (*loginsrv_grpc.LoginReply, error) {
+	if request.Username != "bob" || request.Password != "secret" || request.Extra["otp"] != "123456" {
+		return nil, status.Errorf(codes.PermissionDenied, "wrong credentials")
+	}
+	return &loginsrv_grpc.LoginReply{
+		AccessToken:        "token-1",
+		ExpiresAt:          42,
+		RefreshesRemaining: 2,
+		TokenType:          "bearer",
+		Profile:            &loginsrv_grpc.Profile{Sub: "bob", Groups: []string{"dev"}},
+	}, nil
+}
+
+func (s *legacyServerStub) GetProfile(ctx context.Context, request *loginsrv_grpc.ProfileRequest) (*loginsrv_grpc.Profile, error) {
+	metadata, _ := md.FromIncomingContext(ctx)
+	if auth := metadata.Get(loginsrv_grpc.AuthTokenMetadataKey); len(auth) == 0 || auth[0] != "bearer token-1" {
+		return nil, status.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	return &loginsrv_grpc.Profile{Sub: "bob", Domain: "example.com"}, nil
+}
+
+func (s *legacyServerStub) ListProviders(ctx context.Context, request *loginsrv_grpc.ListProvidersRequest) (*loginsrv_grpc.ListProvidersReply, error) {
+	return &loginsrv_grpc.ListProvidersReply{
+		Providers: []*loginsrv_grpc.LoginProvider{
+			{Name: "htpasswd", Flow: loginsrv_grpc.LoginProvider_PASSWORD},
+			{Name: "github", Flow: loginsrv_grpc.LoginProvider_REDIRECT},
+		},
+	}, nil
+}
+
+func (s *legacyServerStub) WatchSession(request *loginsrv_grpc.WatchSessionRequest, stream loginsrv_grpc.Auth_WatchSessionServer) error {
+	if err := stream.Send(&loginsrv_grpc.SessionEvent{Type: loginsrv_grpc.SessionEvent_TOKEN_REFRESHED, AccessToken: "token-2"}); err != nil {
+		return err
+	}
+	return stream.Send(&loginsrv_grpc.SessionEvent{Type: loginsrv_grpc.SessionEvent_REVOKED, Message: "logged out"})
+}
+
+// dialV1 serves the stub only through the v1 API, rejecting calls the stub does not override
+func dialV1(t *testing.T) *grpc.ClientConn {
+	deny := func(ctx context.Context) (context.Context, error) {
+		return nil, status.Errorf(codes.Unauthenticated, "denied")
+	}
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(grpc_auth.UnaryServerInterceptor(deny)),
+		grpc.StreamInterceptor(grpc_auth.StreamServerInterceptor(deny)),
+	)
+	RegisterAuthServiceServer(server, NewAuthServiceServer(&legacyServerStub{}))
+
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithInsecure(),
+		grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return listener.Dial()
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+	return conn
+}
+
+func TestAuthServiceServerConvertsMessages(t *testing.T) {
+	client := NewAuthServiceClient(dialV1(t))
+	ctx := context.Background()
+
+	login, err := client.AttemptLogin(ctx, &AttemptLoginRequest{
+		Username: "bob",
+		Password: "secret",
+		Extra:    map[string]string{"otp": "123456"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if login.AccessToken != "token-1" || login.ExpiresAt != 42 || login.RefreshesRemaining != 2 || login.TokenType != "bearer" {
+		t.Errorf("unexpected login response %v", login)
+	}
+	if login.Profile.GetSub() != "bob" || len(login.Profile.GetGroups()) != 1 {
+		t.Errorf("unexpected profile %v", login.Profile)
+	}
+
+	providers, err := client.ListProviders(ctx, &ListProvidersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(providers.Providers) != 2 ||
+		providers.Providers[0].Flow != LoginFlow_LOGIN_FLOW_PASSWORD ||
+		providers.Providers[1].Flow != LoginFlow_LOGIN_FLOW_REDIRECT {
+		t.Errorf("unexpected providers %v", providers.Providers)
+	}
+
+	stream, err := client.WatchSession(ctx, &WatchSessionRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var types []SessionEventType
+	for {
+		event, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		types = append(types, event.Type)
+	}
+	if len(types) != 2 ||
+		types[0] != SessionEventType_SESSION_EVENT_TYPE_TOKEN_REFRESHED ||
+		types[1] != SessionEventType_SESSION_EVENT_TYPE_REVOKED {
+		t.Errorf("unexpected events %v", types)
+	}
+}
+
+func TestLegacyAuthClientRunsSession(t *testing.T) {
+	client := NewLegacyAuthClient(NewAuthServiceClient(dialV1(t)))
+	ctx := context.Background()
+
+	_, err := client.AttemptLogin(ctx, &loginsrv_grpc.LoginRequest{Username: "bob", Password: "wrong"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("expected the error code to pass through, got %v", err)
+	}
+
+	session := loginsrv_grpc.NewSession(client)
+	if _, err := session.Profile(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("expected a logged out session, got %v", err)
+	}
+
+	reply, err := client.AttemptLogin(ctx, &loginsrv_grpc.LoginRequest{
+		Username: "bob",
+		Password: "secret",
+		Extra:    map[string]string{"otp": "123456"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Profile.GetSub() != "bob" {
+		t.Errorf("unexpected login reply %v", reply)
+	}
+
+	profile, err := client.GetProfile(
+		md.AppendToOutgoingContext(ctx, loginsrv_grpc.AuthTokenMetadataKey, "bearer "+reply.AccessToken),
+		&loginsrv_grpc.ProfileRequest{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Sub != "bob" || profile.Domain != "example.com" {
+		t.Errorf("unexpected profile %v", profile)
+	}
+
+	providers, err := client.ListProviders(ctx, &loginsrv_grpc.ListProvidersRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if providers.Providers[1].Flow != loginsrv_grpc.LoginProvider_REDIRECT {
+		t.Errorf("unexpected providers %v", providers.Providers)
+	}
+}
@@ -0,0 +1,151 @@
+package loginsrvv1
+
+import (
+	"context"
+
+	loginsrv_grpc "github.com/motia/loginsrv-grpc"
+	"google.golang.org/grpc"
+)
+
+// legacyAuthClient calls the v1 API through the unversioned AuthClient interface
+type legacyAuthClient struct {
+	client AuthServiceClient
+}
+
+// NewLegacyAuthClient lets the unversioned helpers, such as loginsrv_grpc.NewSession
+// or loginsrv_grpc.NewLoginTokenSource, talk to a server only exposing the v1 API
+func NewLegacyAuthClient(client AuthServiceClient) loginsrv_grpc.AuthClient {
+	return &legacyAuthClient{client: client}
+}
+
+func (c *legacyAuthClient) AttemptLogin(ctx context.Context, in *loginsrv_grpc.LoginRequest, opts ...grpc.CallOption) (*loginsrv_grpc.LoginReply, error) {
+	response, err := c.client.AttemptLogin(ctx, &AttemptLoginRequest{
+		Username: in.Username,
+		Password: in.Password,
+		Provider: in.Provider,
+		Extra:    in.Extra,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return toLegacyLoginReply(response), nil
+}
+
+func (c *legacyAuthClient) RefreshToken(ctx context.Context, in *loginsrv_grpc.RefreshRequest, opts ...grpc.CallOption) (*loginsrv_grpc.LoginReply, error) {
+	response, err := c.client.RefreshToken(ctx, &RefreshTokenRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return toLegacyLoginReply(response), nil
+}
+
+func (c *legacyAuthClient) GetProfile(ctx context.Context, in *loginsrv_grpc.ProfileRequest, opts ...grpc.CallOption) (*loginsrv_grpc.Profile, error) {
+	profile, err := c.client.GetProfile(ctx, &GetProfileRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return toLegacyProfile(profile), nil
+}
+
+func (c *legacyAuthClient) Logout(ctx context.Context, in *loginsrv_grpc.LogoutRequest, opts ...grpc.CallOption) (*loginsrv_grpc.LogoutReply, error) {
+	if _, err := c.client.Logout(ctx, &LogoutRequest{}, opts...); err != nil {
+		return nil, err
+	}
+	return &loginsrv_grpc.LogoutReply{}, nil
+}
+
+func (c *legacyAuthClient) ValidateToken(ctx context.Context, in *loginsrv_grpc.ValidateTokenRequest, opts ...grpc.CallOption) (*loginsrv_grpc.ValidateTokenReply, error) {
+	response, err := c.client.ValidateToken(ctx, &ValidateTokenRequest{Token: in.Token}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return toLegacyValidateTokenReply(response), nil
+}
+
+func (c *legacyAuthClient) ValidateTokens(ctx context.Context, in *loginsrv_grpc.ValidateTokensRequest, opts ...grpc.CallOption) (*loginsrv_grpc.ValidateTokensReply, error) {
+	response, err := c.client.ValidateTokens(ctx, &ValidateTokensRequest{Tokens: in.Tokens}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &loginsrv_grpc.ValidateTokensReply{}
+	for _, result := range response.Results {
+		reply.Results = append(reply.Results, toLegacyValidateTokenReply(result))
+	}
+	return reply, nil
+}
+
+func (c *legacyAuthClient) StartOAuthLogin(ctx context.Context, in *loginsrv_grpc.StartOAuthLoginRequest, opts ...grpc.CallOption) (*loginsrv_grpc.StartOAuthLoginReply, error) {
+	response, err := c.client.StartOAuthLogin(ctx, &StartOAuthLoginRequest{Provider: in.Provider}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &loginsrv_grpc.StartOAuthLoginReply{
+		AuthorizationUrl: response.AuthorizationUrl,
+		State:            response.State,
+	}, nil
+}
+
+func (c *legacyAuthClient) CompleteOAuthLogin(ctx context.Context, in *loginsrv_grpc.CompleteOAuthLoginRequest, opts ...grpc.CallOption) (*loginsrv_grpc.LoginReply, error) {
+	response, err := c.client.CompleteOAuthLogin(ctx, &CompleteOAuthLoginRequest{
+		Provider: in.Provider,
+		Code:     in.Code,
+		State:    in.State,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return toLegacyLoginReply(response), nil
+}
+
+func (c *legacyAuthClient) ListProviders(ctx context.Context, in *loginsrv_grpc.ListProvidersRequest, opts ...grpc.CallOption) (*loginsrv_grpc.ListProvidersReply, error) {
+	response, err := c.client.ListProviders(ctx, &ListProvidersRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &loginsrv_grpc.ListProvidersReply{}
+	for _, provider := range response.Providers {
+		reply.Providers = append(reply.Providers, toLegacyProvider(provider))
+	}
+	return reply, nil
+}
+
+func (c *legacyAuthClient) ExchangeToken(ctx context.Context, in *loginsrv_grpc.ExchangeTokenRequest, opts ...grpc.CallOption) (*loginsrv_grpc.ExchangeTokenReply, error) {
+	response, err := c.client.ExchangeToken(ctx, &ExchangeTokenRequest{
+		SubjectToken: in.SubjectToken,
+		Audience:     in.Audience,
+		Scopes:       in.Scopes,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &loginsrv_grpc.ExchangeTokenReply{
+		AccessToken:     response.AccessToken,
+		IssuedTokenType: response.IssuedTokenType,
+		TokenType:       response.TokenType,
+		ExpiresIn:       response.ExpiresIn,
+		Scopes:          response.Scopes,
+	}, nil
+}
+
+func (c *legacyAuthClient) WatchSession(ctx context.Context, in *loginsrv_grpc.WatchSessionRequest, opts ...grpc.CallOption) (loginsrv_grpc.Auth_WatchSessionClient, error) {
+	stream, err := c.client.WatchSession(ctx, &WatchSessionRequest{}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &legacyWatchSessionClient{stream}, nil
+}
+
+// legacyWatchSessionClient converts the events received from the v1 API
+type legacyWatchSessionClient struct {
+	AuthService_WatchSessionClient
+}
+
+func (c *legacyWatchSessionClient) Recv() (*loginsrv_grpc.SessionEvent, error) {
+	event, err := c.AuthService_WatchSessionClient.Recv()
+	if err != nil {
+		return nil, err
+	}
+	return toLegacySessionEvent(event), nil
+}
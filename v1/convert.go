@@ -0,0 +1,136 @@
+package loginsrvv1
+
+import (
+	loginsrv_grpc "github.com/motia/loginsrv-grpc"
+)
+
+var loginFlows = map[loginsrv_grpc.LoginProvider_Flow]LoginFlow{
+	loginsrv_grpc.LoginProvider_PASSWORD: LoginFlow_LOGIN_FLOW_PASSWORD,
+	loginsrv_grpc.LoginProvider_REDIRECT: LoginFlow_LOGIN_FLOW_REDIRECT,
+}
+
+var sessionEventTypes = map[loginsrv_grpc.SessionEvent_Type]SessionEventType{
+	loginsrv_grpc.SessionEvent_TOKEN_REFRESHED: SessionEventType_SESSION_EVENT_TYPE_TOKEN_REFRESHED,
+	loginsrv_grpc.SessionEvent_EXPIRY_WARNING:  SessionEventType_SESSION_EVENT_TYPE_EXPIRY_WARNING,
+	loginsrv_grpc.SessionEvent_EXPIRED:         SessionEventType_SESSION_EVENT_TYPE_EXPIRED,
+	loginsrv_grpc.SessionEvent_REVOKED:         SessionEventType_SESSION_EVENT_TYPE_REVOKED,
+	loginsrv_grpc.SessionEvent_FORCED_LOGOUT:   SessionEventType_SESSION_EVENT_TYPE_FORCED_LOGOUT,
+}
+
+func fromLegacyProfile(p *loginsrv_grpc.Profile) *Profile {
+	if p == nil {
+		return nil
+	}
+	return &Profile{
+		Sub:       p.Sub,
+		Picture:   p.Picture,
+		Name:      p.Name,
+		Email:     p.Email,
+		Origin:    p.Origin,
+		Expiry:    p.Expiry,
+		Refreshes: p.Refreshes,
+		Domain:    p.Domain,
+		Groups:    p.Groups,
+	}
+}
+
+func toLegacyProfile(p *Profile) *loginsrv_grpc.Profile {
+	if p == nil {
+		return nil
+	}
+	return &loginsrv_grpc.Profile{
+		Sub:       p.Sub,
+		Picture:   p.Picture,
+		Name:      p.Name,
+		Email:     p.Email,
+		Origin:    p.Origin,
+		Expiry:    p.Expiry,
+		Refreshes: p.Refreshes,
+		Domain:    p.Domain,
+		Groups:    p.Groups,
+	}
+}
+
+func fromLegacyLoginReply(r *loginsrv_grpc.LoginReply) *LoginResponse {
+	return &LoginResponse{
+		AccessToken:        r.AccessToken,
+		ExpiresAt:          r.ExpiresAt,
+		RefreshesRemaining: r.RefreshesRemaining,
+		TokenType:          r.TokenType,
+		Profile:            fromLegacyProfile(r.Profile),
+	}
+}
+
+func toLegacyLoginReply(r *LoginResponse) *loginsrv_grpc.LoginReply {
+	return &loginsrv_grpc.LoginReply{
+		AccessToken:        r.AccessToken,
+		ExpiresAt:          r.ExpiresAt,
+		RefreshesRemaining: r.RefreshesRemaining,
+		TokenType:          r.TokenType,
+		Profile:            toLegacyProfile(r.Profile),
+	}
+}
+
+func fromLegacyValidateTokenReply(r *loginsrv_grpc.ValidateTokenReply) *ValidateTokenResponse {
+	return &ValidateTokenResponse{
+		Active:    r.Active,
+		Profile:   fromLegacyProfile(r.Profile),
+		ExpiresIn: r.ExpiresIn,
+		Reason:    r.Reason,
+		Error:     r.Error,
+	}
+}
+
+func toLegacyValidateTokenReply(r *ValidateTokenResponse) *loginsrv_grpc.ValidateTokenReply {
+	return &loginsrv_grpc.ValidateTokenReply{
+		Active:    r.Active,
+		Profile:   toLegacyProfile(r.Profile),
+		ExpiresIn: r.ExpiresIn,
+		Reason:    r.Reason,
+		Error:     r.Error,
+	}
+}
+
+func fromLegacyProvider(p *loginsrv_grpc.LoginProvider) *LoginProvider {
+	return &LoginProvider{
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+		Flow:        loginFlows[p.Flow],
+	}
+}
+
+func toLegacyProvider(p *LoginProvider) *loginsrv_grpc.LoginProvider {
+	provider := &loginsrv_grpc.LoginProvider{
+		Name:        p.Name,
+		DisplayName: p.DisplayName,
+	}
+	for legacy, flow := range loginFlows {
+		if flow == p.Flow {
+			provider.Flow = legacy
+		}
+	}
+	return provider
+}
+
+func fromLegacySessionEvent(e *loginsrv_grpc.SessionEvent) *SessionEvent {
+	return &SessionEvent{
+		Type:        sessionEventTypes[e.Type],
+		AccessToken: e.AccessToken,
+		ExpiresAt:   e.ExpiresAt,
+		Message:     e.Message,
+	}
+}
+
+func toLegacySessionEvent(e *SessionEvent) *loginsrv_grpc.SessionEvent {
+	event := &loginsrv_grpc.SessionEvent{
+		AccessToken: e.AccessToken,
+		ExpiresAt:   e.ExpiresAt,
+		Message:     e.Message,
+	}
+	for legacy, t := range sessionEventTypes {
+		if t == e.Type {
+			event.Type = legacy
+		}
+	}
+	return event
+}
@@ -0,0 +1,1736 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: v1/loginsrv.proto
+
+package loginsrvv1
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type LoginFlow int32
+
+const (
+	LoginFlow_LOGIN_FLOW_UNSPECIFIED LoginFlow = 0
+	// username and password sent with AttemptLogin
+	LoginFlow_LOGIN_FLOW_PASSWORD LoginFlow = 1
+	// user agent redirected with StartOAuthLogin
+	LoginFlow_LOGIN_FLOW_REDIRECT LoginFlow = 2
+)
+
+var LoginFlow_name = map[int32]string{
+	0: "LOGIN_FLOW_UNSPECIFIED",
+	1: "LOGIN_FLOW_PASSWORD",
+	2: "LOGIN_FLOW_REDIRECT",
+}
+
+var LoginFlow_value = map[string]int32{
+	"LOGIN_FLOW_UNSPECIFIED": 0,
+	"LOGIN_FLOW_PASSWORD":    1,
+	"LOGIN_FLOW_REDIRECT":    2,
+}
+
+func (x LoginFlow) String() string {
+	return proto.EnumName(LoginFlow_name, int32(x))
+}
+
+func (LoginFlow) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{0}
+}
+
+type SessionEventType int32
+
+const (
+	SessionEventType_SESSION_EVENT_TYPE_UNSPECIFIED SessionEventType = 0
+	// the token was refreshed, access_token carries the new one
+	SessionEventType_SESSION_EVENT_TYPE_TOKEN_REFRESHED SessionEventType = 1
+	// the token can no longer be refreshed and expires at expires_at
+	SessionEventType_SESSION_EVENT_TYPE_EXPIRY_WARNING SessionEventType = 2
+	// the token expired, the stream ends
+	SessionEventType_SESSION_EVENT_TYPE_EXPIRED SessionEventType = 3
+	// the token was revoked by a logout, the stream ends
+	SessionEventType_SESSION_EVENT_TYPE_REVOKED SessionEventType = 4
+	// the session was ended by the server, the stream ends
+	SessionEventType_SESSION_EVENT_TYPE_FORCED_LOGOUT SessionEventType = 5
+)
+
+var SessionEventType_name = map[int32]string{
+	0: "SESSION_EVENT_TYPE_UNSPECIFIED",
+	1: "SESSION_EVENT_TYPE_TOKEN_REFRESHED",
+	2: "SESSION_EVENT_TYPE_EXPIRY_WARNING",
+	3: "SESSION_EVENT_TYPE_EXPIRED",
+	4: "SESSION_EVENT_TYPE_REVOKED",
+	5: "SESSION_EVENT_TYPE_FORCED_LOGOUT",
+}
+
+var SessionEventType_value = map[string]int32{
+	"SESSION_EVENT_TYPE_UNSPECIFIED":     0,
+	"SESSION_EVENT_TYPE_TOKEN_REFRESHED": 1,
+	"SESSION_EVENT_TYPE_EXPIRY_WARNING":  2,
+	"SESSION_EVENT_TYPE_EXPIRED":         3,
+	"SESSION_EVENT_TYPE_REVOKED":         4,
+	"SESSION_EVENT_TYPE_FORCED_LOGOUT":   5,
+}
+
+func (x SessionEventType) String() string {
+	return proto.EnumName(SessionEventType_name, int32(x))
+}
+
+func (SessionEventType) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{1}
+}
+
+type AttemptLoginRequest struct {
+	Username string `protobuf:"bytes,1,opt,name=username,proto3" json:"username,omitempty"`
+	Password string `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	// password provider declared on the server, the request is posted to /login/<provider>
+	// loginsrv still tries all of its password backends, the path does not select one
+	Provider string `protobuf:"bytes,3,opt,name=provider,proto3" json:"provider,omitempty"`
+	// additional form fields, e.g. a domain or an otp
+	Extra                map[string]string `protobuf:"bytes,4,rep,name=extra,proto3" json:"extra,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
+	XXX_unrecognized     []byte            `json:"-"`
+	XXX_sizecache        int32             `json:"-"`
+}
+
+func (m *AttemptLoginRequest) Reset()         { *m = AttemptLoginRequest{} }
+func (m *AttemptLoginRequest) String() string { return proto.CompactTextString(m) }
+func (*AttemptLoginRequest) ProtoMessage()    {}
+func (*AttemptLoginRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{0}
+}
+
+func (m *AttemptLoginRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AttemptLoginRequest.Unmarshal(m, b)
+}
+func (m *AttemptLoginRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AttemptLoginRequest.Marshal(b, m, deterministic)
+}
+func (m *AttemptLoginRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AttemptLoginRequest.Merge(m, src)
+}
+func (m *AttemptLoginRequest) XXX_Size() int {
+	return xxx_messageInfo_AttemptLoginRequest.Size(m)
+}
+func (m *AttemptLoginRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AttemptLoginRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AttemptLoginRequest proto.InternalMessageInfo
+
+func (m *AttemptLoginRequest) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *AttemptLoginRequest) GetPassword() string {
+	if m != nil {
+		return m.Password
+	}
+	return ""
+}
+
+func (m *AttemptLoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *AttemptLoginRequest) GetExtra() map[string]string {
+	if m != nil {
+		return m.Extra
+	}
+	return nil
+}
+
+type RefreshTokenRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RefreshTokenRequest) Reset()         { *m = RefreshTokenRequest{} }
+func (m *RefreshTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*RefreshTokenRequest) ProtoMessage()    {}
+func (*RefreshTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{1}
+}
+
+func (m *RefreshTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RefreshTokenRequest.Unmarshal(m, b)
+}
+func (m *RefreshTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RefreshTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *RefreshTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RefreshTokenRequest.Merge(m, src)
+}
+func (m *RefreshTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_RefreshTokenRequest.Size(m)
+}
+func (m *RefreshTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RefreshTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RefreshTokenRequest proto.InternalMessageInfo
+
+type LoginResponse struct {
+	AccessToken string `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// unix time of the token expiry
+	ExpiresAt int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// refreshes left for the token, -1 when unknown
+	RefreshesRemaining int32 `protobuf:"varint,3,opt,name=refreshes_remaining,json=refreshesRemaining,proto3" json:"refreshes_remaining,omitempty"`
+	// always bearer
+	TokenType            string   `protobuf:"bytes,4,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	Profile              *Profile `protobuf:"bytes,5,opt,name=profile,proto3" json:"profile,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LoginResponse) Reset()         { *m = LoginResponse{} }
+func (m *LoginResponse) String() string { return proto.CompactTextString(m) }
+func (*LoginResponse) ProtoMessage()    {}
+func (*LoginResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{2}
+}
+
+func (m *LoginResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginResponse.Unmarshal(m, b)
+}
+func (m *LoginResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginResponse.Marshal(b, m, deterministic)
+}
+func (m *LoginResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginResponse.Merge(m, src)
+}
+func (m *LoginResponse) XXX_Size() int {
+	return xxx_messageInfo_LoginResponse.Size(m)
+}
+func (m *LoginResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginResponse proto.InternalMessageInfo
+
+func (m *LoginResponse) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *LoginResponse) GetRefreshesRemaining() int32 {
+	if m != nil {
+		return m.RefreshesRemaining
+	}
+	return 0
+}
+
+func (m *LoginResponse) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
+	}
+	return ""
+}
+
+func (m *LoginResponse) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+type GetProfileRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetProfileRequest) Reset()         { *m = GetProfileRequest{} }
+func (m *GetProfileRequest) String() string { return proto.CompactTextString(m) }
+func (*GetProfileRequest) ProtoMessage()    {}
+func (*GetProfileRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{3}
+}
+
+func (m *GetProfileRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetProfileRequest.Unmarshal(m, b)
+}
+func (m *GetProfileRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetProfileRequest.Marshal(b, m, deterministic)
+}
+func (m *GetProfileRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetProfileRequest.Merge(m, src)
+}
+func (m *GetProfileRequest) XXX_Size() int {
+	return xxx_messageInfo_GetProfileRequest.Size(m)
+}
+func (m *GetProfileRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetProfileRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetProfileRequest proto.InternalMessageInfo
+
+type Profile struct {
+	Sub                  string   `protobuf:"bytes,1,opt,name=sub,proto3" json:"sub,omitempty"`
+	Picture              string   `protobuf:"bytes,2,opt,name=picture,proto3" json:"picture,omitempty"`
+	Name                 string   `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Email                string   `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Origin               string   `protobuf:"bytes,5,opt,name=origin,proto3" json:"origin,omitempty"`
+	Expiry               int64    `protobuf:"varint,6,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Refreshes            int32    `protobuf:"varint,7,opt,name=refreshes,proto3" json:"refreshes,omitempty"`
+	Domain               string   `protobuf:"bytes,8,opt,name=domain,proto3" json:"domain,omitempty"`
+	Groups               []string `protobuf:"bytes,9,rep,name=groups,proto3" json:"groups,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Profile) Reset()         { *m = Profile{} }
+func (m *Profile) String() string { return proto.CompactTextString(m) }
+func (*Profile) ProtoMessage()    {}
+func (*Profile) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{4}
+}
+
+func (m *Profile) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Profile.Unmarshal(m, b)
+}
+func (m *Profile) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Profile.Marshal(b, m, deterministic)
+}
+func (m *Profile) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Profile.Merge(m, src)
+}
+func (m *Profile) XXX_Size() int {
+	return xxx_messageInfo_Profile.Size(m)
+}
+func (m *Profile) XXX_DiscardUnknown() {
+	xxx_messageInfo_Profile.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Profile proto.InternalMessageInfo
+
+func (m *Profile) GetSub() string {
+	if m != nil {
+		return m.Sub
+	}
+	return ""
+}
+
+func (m *Profile) GetPicture() string {
+	if m != nil {
+		return m.Picture
+	}
+	return ""
+}
+
+func (m *Profile) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Profile) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *Profile) GetOrigin() string {
+	if m != nil {
+		return m.Origin
+	}
+	return ""
+}
+
+func (m *Profile) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+func (m *Profile) GetRefreshes() int32 {
+	if m != nil {
+		return m.Refreshes
+	}
+	return 0
+}
+
+func (m *Profile) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
+func (m *Profile) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+type LogoutRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogoutRequest) Reset()         { *m = LogoutRequest{} }
+func (m *LogoutRequest) String() string { return proto.CompactTextString(m) }
+func (*LogoutRequest) ProtoMessage()    {}
+func (*LogoutRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{5}
+}
+
+func (m *LogoutRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutRequest.Unmarshal(m, b)
+}
+func (m *LogoutRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutRequest.Marshal(b, m, deterministic)
+}
+func (m *LogoutRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutRequest.Merge(m, src)
+}
+func (m *LogoutRequest) XXX_Size() int {
+	return xxx_messageInfo_LogoutRequest.Size(m)
+}
+func (m *LogoutRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogoutRequest proto.InternalMessageInfo
+
+type LogoutResponse struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *LogoutResponse) Reset()         { *m = LogoutResponse{} }
+func (m *LogoutResponse) String() string { return proto.CompactTextString(m) }
+func (*LogoutResponse) ProtoMessage()    {}
+func (*LogoutResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{6}
+}
+
+func (m *LogoutResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LogoutResponse.Unmarshal(m, b)
+}
+func (m *LogoutResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LogoutResponse.Marshal(b, m, deterministic)
+}
+func (m *LogoutResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LogoutResponse.Merge(m, src)
+}
+func (m *LogoutResponse) XXX_Size() int {
+	return xxx_messageInfo_LogoutResponse.Size(m)
+}
+func (m *LogoutResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_LogoutResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LogoutResponse proto.InternalMessageInfo
+
+type ValidateTokenRequest struct {
+	Token                string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateTokenRequest) Reset()         { *m = ValidateTokenRequest{} }
+func (m *ValidateTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokenRequest) ProtoMessage()    {}
+func (*ValidateTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{7}
+}
+
+func (m *ValidateTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokenRequest.Unmarshal(m, b)
+}
+func (m *ValidateTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokenRequest.Merge(m, src)
+}
+func (m *ValidateTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokenRequest.Size(m)
+}
+func (m *ValidateTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTokenRequest proto.InternalMessageInfo
+
+func (m *ValidateTokenRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type ValidateTokenResponse struct {
+	Active  bool     `protobuf:"varint,1,opt,name=active,proto3" json:"active,omitempty"`
+	Profile *Profile `protobuf:"bytes,2,opt,name=profile,proto3" json:"profile,omitempty"`
+	// seconds left before the token expires
+	ExpiresIn int64 `protobuf:"varint,3,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	// why the token is not active
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// why the token could not be checked, e.g. loginsrv is unreachable,
+	// only set in the results of ValidateTokens
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateTokenResponse) Reset()         { *m = ValidateTokenResponse{} }
+func (m *ValidateTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokenResponse) ProtoMessage()    {}
+func (*ValidateTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{8}
+}
+
+func (m *ValidateTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokenResponse.Unmarshal(m, b)
+}
+func (m *ValidateTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokenResponse.Merge(m, src)
+}
+func (m *ValidateTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokenResponse.Size(m)
+}
+func (m *ValidateTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTokenResponse proto.InternalMessageInfo
+
+func (m *ValidateTokenResponse) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *ValidateTokenResponse) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *ValidateTokenResponse) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *ValidateTokenResponse) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *ValidateTokenResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ValidateTokensRequest struct {
+	Tokens               []string `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidateTokensRequest) Reset()         { *m = ValidateTokensRequest{} }
+func (m *ValidateTokensRequest) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokensRequest) ProtoMessage()    {}
+func (*ValidateTokensRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{9}
+}
+
+func (m *ValidateTokensRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokensRequest.Unmarshal(m, b)
+}
+func (m *ValidateTokensRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokensRequest.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokensRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokensRequest.Merge(m, src)
+}
+func (m *ValidateTokensRequest) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokensRequest.Size(m)
+}
+func (m *ValidateTokensRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokensRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTokensRequest proto.InternalMessageInfo
+
+func (m *ValidateTokensRequest) GetTokens() []string {
+	if m != nil {
+		return m.Tokens
+	}
+	return nil
+}
+
+type ValidateTokensResponse struct {
+	// one result per requested token, in the same order
+	Results              []*ValidateTokenResponse `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}                 `json:"-"`
+	XXX_unrecognized     []byte                   `json:"-"`
+	XXX_sizecache        int32                    `json:"-"`
+}
+
+func (m *ValidateTokensResponse) Reset()         { *m = ValidateTokensResponse{} }
+func (m *ValidateTokensResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidateTokensResponse) ProtoMessage()    {}
+func (*ValidateTokensResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{10}
+}
+
+func (m *ValidateTokensResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidateTokensResponse.Unmarshal(m, b)
+}
+func (m *ValidateTokensResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidateTokensResponse.Marshal(b, m, deterministic)
+}
+func (m *ValidateTokensResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidateTokensResponse.Merge(m, src)
+}
+func (m *ValidateTokensResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidateTokensResponse.Size(m)
+}
+func (m *ValidateTokensResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidateTokensResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidateTokensResponse proto.InternalMessageInfo
+
+func (m *ValidateTokensResponse) GetResults() []*ValidateTokenResponse {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+type StartOAuthLoginRequest struct {
+	// loginsrv oauth provider, e.g. github or google
+	Provider             string   `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartOAuthLoginRequest) Reset()         { *m = StartOAuthLoginRequest{} }
+func (m *StartOAuthLoginRequest) String() string { return proto.CompactTextString(m) }
+func (*StartOAuthLoginRequest) ProtoMessage()    {}
+func (*StartOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{11}
+}
+
+func (m *StartOAuthLoginRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartOAuthLoginRequest.Unmarshal(m, b)
+}
+func (m *StartOAuthLoginRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartOAuthLoginRequest.Marshal(b, m, deterministic)
+}
+func (m *StartOAuthLoginRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartOAuthLoginRequest.Merge(m, src)
+}
+func (m *StartOAuthLoginRequest) XXX_Size() int {
+	return xxx_messageInfo_StartOAuthLoginRequest.Size(m)
+}
+func (m *StartOAuthLoginRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartOAuthLoginRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartOAuthLoginRequest proto.InternalMessageInfo
+
+func (m *StartOAuthLoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+type StartOAuthLoginResponse struct {
+	// the user agent is sent there to authorize the login, the client intercepts
+	// the redirect of the provider to the loginsrv callback to read the code
+	AuthorizationUrl string `protobuf:"bytes,1,opt,name=authorization_url,json=authorizationUrl,proto3" json:"authorization_url,omitempty"`
+	// must be passed back to CompleteOAuthLogin
+	State                string   `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *StartOAuthLoginResponse) Reset()         { *m = StartOAuthLoginResponse{} }
+func (m *StartOAuthLoginResponse) String() string { return proto.CompactTextString(m) }
+func (*StartOAuthLoginResponse) ProtoMessage()    {}
+func (*StartOAuthLoginResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{12}
+}
+
+func (m *StartOAuthLoginResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_StartOAuthLoginResponse.Unmarshal(m, b)
+}
+func (m *StartOAuthLoginResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_StartOAuthLoginResponse.Marshal(b, m, deterministic)
+}
+func (m *StartOAuthLoginResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_StartOAuthLoginResponse.Merge(m, src)
+}
+func (m *StartOAuthLoginResponse) XXX_Size() int {
+	return xxx_messageInfo_StartOAuthLoginResponse.Size(m)
+}
+func (m *StartOAuthLoginResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_StartOAuthLoginResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_StartOAuthLoginResponse proto.InternalMessageInfo
+
+func (m *StartOAuthLoginResponse) GetAuthorizationUrl() string {
+	if m != nil {
+		return m.AuthorizationUrl
+	}
+	return ""
+}
+
+func (m *StartOAuthLoginResponse) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type CompleteOAuthLoginRequest struct {
+	Provider string `protobuf:"bytes,1,opt,name=provider,proto3" json:"provider,omitempty"`
+	// code and state received by the provider callback
+	Code                 string   `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	State                string   `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CompleteOAuthLoginRequest) Reset()         { *m = CompleteOAuthLoginRequest{} }
+func (m *CompleteOAuthLoginRequest) String() string { return proto.CompactTextString(m) }
+func (*CompleteOAuthLoginRequest) ProtoMessage()    {}
+func (*CompleteOAuthLoginRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{13}
+}
+
+func (m *CompleteOAuthLoginRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Unmarshal(m, b)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Marshal(b, m, deterministic)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompleteOAuthLoginRequest.Merge(m, src)
+}
+func (m *CompleteOAuthLoginRequest) XXX_Size() int {
+	return xxx_messageInfo_CompleteOAuthLoginRequest.Size(m)
+}
+func (m *CompleteOAuthLoginRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompleteOAuthLoginRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompleteOAuthLoginRequest proto.InternalMessageInfo
+
+func (m *CompleteOAuthLoginRequest) GetProvider() string {
+	if m != nil {
+		return m.Provider
+	}
+	return ""
+}
+
+func (m *CompleteOAuthLoginRequest) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *CompleteOAuthLoginRequest) GetState() string {
+	if m != nil {
+		return m.State
+	}
+	return ""
+}
+
+type ListProvidersRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListProvidersRequest) Reset()         { *m = ListProvidersRequest{} }
+func (m *ListProvidersRequest) String() string { return proto.CompactTextString(m) }
+func (*ListProvidersRequest) ProtoMessage()    {}
+func (*ListProvidersRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{14}
+}
+
+func (m *ListProvidersRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProvidersRequest.Unmarshal(m, b)
+}
+func (m *ListProvidersRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProvidersRequest.Marshal(b, m, deterministic)
+}
+func (m *ListProvidersRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProvidersRequest.Merge(m, src)
+}
+func (m *ListProvidersRequest) XXX_Size() int {
+	return xxx_messageInfo_ListProvidersRequest.Size(m)
+}
+func (m *ListProvidersRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProvidersRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProvidersRequest proto.InternalMessageInfo
+
+type ListProvidersResponse struct {
+	Providers            []*LoginProvider `protobuf:"bytes,1,rep,name=providers,proto3" json:"providers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *ListProvidersResponse) Reset()         { *m = ListProvidersResponse{} }
+func (m *ListProvidersResponse) String() string { return proto.CompactTextString(m) }
+func (*ListProvidersResponse) ProtoMessage()    {}
+func (*ListProvidersResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{15}
+}
+
+func (m *ListProvidersResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListProvidersResponse.Unmarshal(m, b)
+}
+func (m *ListProvidersResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListProvidersResponse.Marshal(b, m, deterministic)
+}
+func (m *ListProvidersResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListProvidersResponse.Merge(m, src)
+}
+func (m *ListProvidersResponse) XXX_Size() int {
+	return xxx_messageInfo_ListProvidersResponse.Size(m)
+}
+func (m *ListProvidersResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListProvidersResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListProvidersResponse proto.InternalMessageInfo
+
+func (m *ListProvidersResponse) GetProviders() []*LoginProvider {
+	if m != nil {
+		return m.Providers
+	}
+	return nil
+}
+
+type LoginProvider struct {
+	Name                 string    `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	DisplayName          string    `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Flow                 LoginFlow `protobuf:"varint,3,opt,name=flow,proto3,enum=loginsrv.v1.LoginFlow" json:"flow,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *LoginProvider) Reset()         { *m = LoginProvider{} }
+func (m *LoginProvider) String() string { return proto.CompactTextString(m) }
+func (*LoginProvider) ProtoMessage()    {}
+func (*LoginProvider) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{16}
+}
+
+func (m *LoginProvider) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_LoginProvider.Unmarshal(m, b)
+}
+func (m *LoginProvider) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_LoginProvider.Marshal(b, m, deterministic)
+}
+func (m *LoginProvider) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_LoginProvider.Merge(m, src)
+}
+func (m *LoginProvider) XXX_Size() int {
+	return xxx_messageInfo_LoginProvider.Size(m)
+}
+func (m *LoginProvider) XXX_DiscardUnknown() {
+	xxx_messageInfo_LoginProvider.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_LoginProvider proto.InternalMessageInfo
+
+func (m *LoginProvider) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *LoginProvider) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *LoginProvider) GetFlow() LoginFlow {
+	if m != nil {
+		return m.Flow
+	}
+	return LoginFlow_LOGIN_FLOW_UNSPECIFIED
+}
+
+type WatchSessionRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchSessionRequest) Reset()         { *m = WatchSessionRequest{} }
+func (m *WatchSessionRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchSessionRequest) ProtoMessage()    {}
+func (*WatchSessionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{17}
+}
+
+func (m *WatchSessionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchSessionRequest.Unmarshal(m, b)
+}
+func (m *WatchSessionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchSessionRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchSessionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchSessionRequest.Merge(m, src)
+}
+func (m *WatchSessionRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchSessionRequest.Size(m)
+}
+func (m *WatchSessionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchSessionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchSessionRequest proto.InternalMessageInfo
+
+type SessionEvent struct {
+	Type                 SessionEventType `protobuf:"varint,1,opt,name=type,proto3,enum=loginsrv.v1.SessionEventType" json:"type,omitempty"`
+	AccessToken          string           `protobuf:"bytes,2,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	ExpiresAt            int64            `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Message              string           `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
+	XXX_unrecognized     []byte           `json:"-"`
+	XXX_sizecache        int32            `json:"-"`
+}
+
+func (m *SessionEvent) Reset()         { *m = SessionEvent{} }
+func (m *SessionEvent) String() string { return proto.CompactTextString(m) }
+func (*SessionEvent) ProtoMessage()    {}
+func (*SessionEvent) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{18}
+}
+
+func (m *SessionEvent) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SessionEvent.Unmarshal(m, b)
+}
+func (m *SessionEvent) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SessionEvent.Marshal(b, m, deterministic)
+}
+func (m *SessionEvent) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SessionEvent.Merge(m, src)
+}
+func (m *SessionEvent) XXX_Size() int {
+	return xxx_messageInfo_SessionEvent.Size(m)
+}
+func (m *SessionEvent) XXX_DiscardUnknown() {
+	xxx_messageInfo_SessionEvent.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SessionEvent proto.InternalMessageInfo
+
+func (m *SessionEvent) GetType() SessionEventType {
+	if m != nil {
+		return m.Type
+	}
+	return SessionEventType_SESSION_EVENT_TYPE_UNSPECIFIED
+}
+
+func (m *SessionEvent) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+func (m *SessionEvent) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *SessionEvent) GetMessage() string {
+	if m != nil {
+		return m.Message
+	}
+	return ""
+}
+
+type ExchangeTokenRequest struct {
+	// token of the user the caller acts for, issued by loginsrv or by a previous exchange
+	SubjectToken string `protobuf:"bytes,1,opt,name=subject_token,json=subjectToken,proto3" json:"subject_token,omitempty"`
+	// service the new token is meant for
+	Audience string `protobuf:"bytes,2,opt,name=audience,proto3" json:"audience,omitempty"`
+	// scopes of the new token, within the scopes of the subject token when it has some
+	Scopes               []string `protobuf:"bytes,3,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExchangeTokenRequest) Reset()         { *m = ExchangeTokenRequest{} }
+func (m *ExchangeTokenRequest) String() string { return proto.CompactTextString(m) }
+func (*ExchangeTokenRequest) ProtoMessage()    {}
+func (*ExchangeTokenRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{19}
+}
+
+func (m *ExchangeTokenRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExchangeTokenRequest.Unmarshal(m, b)
+}
+func (m *ExchangeTokenRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExchangeTokenRequest.Marshal(b, m, deterministic)
+}
+func (m *ExchangeTokenRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExchangeTokenRequest.Merge(m, src)
+}
+func (m *ExchangeTokenRequest) XXX_Size() int {
+	return xxx_messageInfo_ExchangeTokenRequest.Size(m)
+}
+func (m *ExchangeTokenRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExchangeTokenRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExchangeTokenRequest proto.InternalMessageInfo
+
+func (m *ExchangeTokenRequest) GetSubjectToken() string {
+	if m != nil {
+		return m.SubjectToken
+	}
+	return ""
+}
+
+func (m *ExchangeTokenRequest) GetAudience() string {
+	if m != nil {
+		return m.Audience
+	}
+	return ""
+}
+
+func (m *ExchangeTokenRequest) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+type ExchangeTokenResponse struct {
+	AccessToken string `protobuf:"bytes,1,opt,name=access_token,json=accessToken,proto3" json:"access_token,omitempty"`
+	// always urn:ietf:params:oauth:token-type:jwt
+	IssuedTokenType string `protobuf:"bytes,2,opt,name=issued_token_type,json=issuedTokenType,proto3" json:"issued_token_type,omitempty"`
+	// always bearer
+	TokenType string `protobuf:"bytes,3,opt,name=token_type,json=tokenType,proto3" json:"token_type,omitempty"`
+	// seconds left before the token expires
+	ExpiresIn            int64    `protobuf:"varint,4,opt,name=expires_in,json=expiresIn,proto3" json:"expires_in,omitempty"`
+	Scopes               []string `protobuf:"bytes,5,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ExchangeTokenResponse) Reset()         { *m = ExchangeTokenResponse{} }
+func (m *ExchangeTokenResponse) String() string { return proto.CompactTextString(m) }
+func (*ExchangeTokenResponse) ProtoMessage()    {}
+func (*ExchangeTokenResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e25613e44d690db3, []int{20}
+}
+
+func (m *ExchangeTokenResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ExchangeTokenResponse.Unmarshal(m, b)
+}
+func (m *ExchangeTokenResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ExchangeTokenResponse.Marshal(b, m, deterministic)
+}
+func (m *ExchangeTokenResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ExchangeTokenResponse.Merge(m, src)
+}
+func (m *ExchangeTokenResponse) XXX_Size() int {
+	return xxx_messageInfo_ExchangeTokenResponse.Size(m)
+}
+func (m *ExchangeTokenResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ExchangeTokenResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ExchangeTokenResponse proto.InternalMessageInfo
+
+func (m *ExchangeTokenResponse) GetAccessToken() string {
+	if m != nil {
+		return m.AccessToken
+	}
+	return ""
+}
+
+func (m *ExchangeTokenResponse) GetIssuedTokenType() string {
+	if m != nil {
+		return m.IssuedTokenType
+	}
+	return ""
+}
+
+func (m *ExchangeTokenResponse) GetTokenType() string {
+	if m != nil {
+		return m.TokenType
+	}
+	return ""
+}
+
+func (m *ExchangeTokenResponse) GetExpiresIn() int64 {
+	if m != nil {
+		return m.ExpiresIn
+	}
+	return 0
+}
+
+func (m *ExchangeTokenResponse) GetScopes() []string {
+	if m != nil {
+		return m.Scopes
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterEnum("loginsrv.v1.LoginFlow", LoginFlow_name, LoginFlow_value)
+	proto.RegisterEnum("loginsrv.v1.SessionEventType", SessionEventType_name, SessionEventType_value)
+	proto.RegisterType((*AttemptLoginRequest)(nil), "loginsrv.v1.AttemptLoginRequest")
+	proto.RegisterMapType((map[string]string)(nil), "loginsrv.v1.AttemptLoginRequest.ExtraEntry")
+	proto.RegisterType((*RefreshTokenRequest)(nil), "loginsrv.v1.RefreshTokenRequest")
+	proto.RegisterType((*LoginResponse)(nil), "loginsrv.v1.LoginResponse")
+	proto.RegisterType((*GetProfileRequest)(nil), "loginsrv.v1.GetProfileRequest")
+	proto.RegisterType((*Profile)(nil), "loginsrv.v1.Profile")
+	proto.RegisterType((*LogoutRequest)(nil), "loginsrv.v1.LogoutRequest")
+	proto.RegisterType((*LogoutResponse)(nil), "loginsrv.v1.LogoutResponse")
+	proto.RegisterType((*ValidateTokenRequest)(nil), "loginsrv.v1.ValidateTokenRequest")
+	proto.RegisterType((*ValidateTokenResponse)(nil), "loginsrv.v1.ValidateTokenResponse")
+	proto.RegisterType((*ValidateTokensRequest)(nil), "loginsrv.v1.ValidateTokensRequest")
+	proto.RegisterType((*ValidateTokensResponse)(nil), "loginsrv.v1.ValidateTokensResponse")
+	proto.RegisterType((*StartOAuthLoginRequest)(nil), "loginsrv.v1.StartOAuthLoginRequest")
+	proto.RegisterType((*StartOAuthLoginResponse)(nil), "loginsrv.v1.StartOAuthLoginResponse")
+	proto.RegisterType((*CompleteOAuthLoginRequest)(nil), "loginsrv.v1.CompleteOAuthLoginRequest")
+	proto.RegisterType((*ListProvidersRequest)(nil), "loginsrv.v1.ListProvidersRequest")
+	proto.RegisterType((*ListProvidersResponse)(nil), "loginsrv.v1.ListProvidersResponse")
+	proto.RegisterType((*LoginProvider)(nil), "loginsrv.v1.LoginProvider")
+	proto.RegisterType((*WatchSessionRequest)(nil), "loginsrv.v1.WatchSessionRequest")
+	proto.RegisterType((*SessionEvent)(nil), "loginsrv.v1.SessionEvent")
+	proto.RegisterType((*ExchangeTokenRequest)(nil), "loginsrv.v1.ExchangeTokenRequest")
+	proto.RegisterType((*ExchangeTokenResponse)(nil), "loginsrv.v1.ExchangeTokenResponse")
+}
+
+func init() { proto.RegisterFile("v1/loginsrv.proto", fileDescriptor_e25613e44d690db3) }
+
+var fileDescriptor_e25613e44d690db3 = []byte{
+	// 1284 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x57, 0xdd, 0x52, 0xdb, 0xc6,
+	0x17, 0x47, 0xd8, 0x60, 0x7c, 0x0c, 0x89, 0x59, 0xc0, 0x51, 0xf4, 0xff, 0x27, 0x03, 0x4a, 0x9a,
+	0xc9, 0x90, 0xd4, 0x14, 0xda, 0x8b, 0x4c, 0xdb, 0x1b, 0x8a, 0x05, 0xf5, 0x84, 0xb1, 0xa9, 0xec,
+	0x40, 0x92, 0x76, 0xaa, 0x11, 0xf6, 0xc6, 0xa8, 0xb1, 0x25, 0x75, 0x77, 0xe5, 0x84, 0xbe, 0x4b,
+	0xdf, 0xa0, 0x17, 0x7d, 0x80, 0xbe, 0x44, 0x1f, 0xa0, 0xb7, 0xbd, 0xe9, 0x4b, 0x74, 0xf6, 0x43,
+	0x42, 0x12, 0xe2, 0xa3, 0x77, 0x3e, 0xdf, 0xe7, 0xfc, 0x76, 0x75, 0x7e, 0x6b, 0x58, 0x9e, 0x6e,
+	0x6f, 0x8d, 0x83, 0x91, 0xe7, 0x53, 0x32, 0x6d, 0x86, 0x24, 0x60, 0x01, 0xaa, 0x25, 0xf2, 0x74,
+	0xdb, 0xfc, 0x5b, 0x83, 0x95, 0x5d, 0xc6, 0xf0, 0x24, 0x64, 0x87, 0x5c, 0x6d, 0xe3, 0x9f, 0x23,
+	0x4c, 0x19, 0x32, 0x60, 0x21, 0xa2, 0x98, 0xf8, 0xee, 0x04, 0xeb, 0xda, 0xba, 0xf6, 0xb4, 0x6a,
+	0x27, 0x32, 0xb7, 0x85, 0x2e, 0xa5, 0x1f, 0x02, 0x32, 0xd4, 0x67, 0xa5, 0x2d, 0x96, 0x85, 0x8d,
+	0x04, 0x53, 0x6f, 0x88, 0x89, 0x5e, 0x52, 0x36, 0x25, 0xa3, 0x5d, 0x98, 0xc3, 0x1f, 0x19, 0x71,
+	0xf5, 0xf2, 0x7a, 0xe9, 0x69, 0x6d, 0xe7, 0x59, 0x33, 0xd5, 0x48, 0xb3, 0xa0, 0x89, 0xa6, 0xc5,
+	0xbd, 0x2d, 0x9f, 0x91, 0x73, 0x5b, 0x46, 0x1a, 0x2f, 0x00, 0x2e, 0x94, 0xa8, 0x0e, 0xa5, 0xf7,
+	0xf8, 0x5c, 0xf5, 0xc7, 0x7f, 0xa2, 0x55, 0x98, 0x9b, 0xba, 0xe3, 0x08, 0xab, 0xbe, 0xa4, 0xf0,
+	0xe5, 0xec, 0x0b, 0xcd, 0x5c, 0x83, 0x15, 0x1b, 0xbf, 0x23, 0x98, 0x9e, 0xf5, 0x83, 0xf7, 0x38,
+	0x2e, 0x61, 0xfe, 0xa9, 0xc1, 0x92, 0xaa, 0x49, 0xc3, 0xc0, 0xa7, 0x18, 0x6d, 0xc0, 0xa2, 0x3b,
+	0x18, 0x60, 0x4a, 0x1d, 0xc6, 0x1d, 0x55, 0xf6, 0x9a, 0xd4, 0x89, 0x58, 0xf4, 0x00, 0x00, 0x7f,
+	0x0c, 0x3d, 0x82, 0xa9, 0xe3, 0x32, 0x51, 0xaa, 0x64, 0x57, 0x95, 0x66, 0x97, 0xa1, 0x2d, 0x58,
+	0x21, 0xb2, 0x14, 0xa6, 0x0e, 0xc1, 0x13, 0xd7, 0xf3, 0x3d, 0x7f, 0x24, 0xe0, 0x98, 0xb3, 0x51,
+	0x62, 0xb2, 0x63, 0x0b, 0xcf, 0x27, 0x6a, 0x39, 0xec, 0x3c, 0xc4, 0x7a, 0x59, 0x14, 0xac, 0x0a,
+	0x4d, 0xff, 0x3c, 0xc4, 0xa8, 0x09, 0x95, 0x90, 0x04, 0xef, 0xbc, 0x31, 0xd6, 0xe7, 0xd6, 0xb5,
+	0xa7, 0xb5, 0x9d, 0xd5, 0x0c, 0x72, 0x47, 0xd2, 0x66, 0xc7, 0x4e, 0xe6, 0x0a, 0x2c, 0x1f, 0x60,
+	0x16, 0xab, 0xd5, 0xa0, 0x7f, 0x69, 0x50, 0x51, 0x2a, 0x8e, 0x1b, 0x8d, 0x4e, 0x63, 0xdc, 0x68,
+	0x74, 0x8a, 0x74, 0xa8, 0x84, 0xde, 0x80, 0x45, 0x24, 0x46, 0x2e, 0x16, 0x11, 0x82, 0xb2, 0xb8,
+	0x04, 0xf2, 0x30, 0xc5, 0x6f, 0x8e, 0x32, 0xef, 0x7d, 0xac, 0x5a, 0x95, 0x02, 0x6a, 0xc0, 0x7c,
+	0x40, 0xbc, 0x91, 0xe7, 0x8b, 0x2e, 0xab, 0xb6, 0x92, 0xb8, 0x5e, 0x60, 0x73, 0xae, 0xcf, 0x0b,
+	0xa4, 0x94, 0x84, 0xfe, 0x0f, 0xd5, 0x04, 0x0b, 0xbd, 0x22, 0xc0, 0xb9, 0x50, 0xf0, 0xa8, 0x61,
+	0xc0, 0x01, 0xd2, 0x17, 0x64, 0x36, 0x29, 0x71, 0xfd, 0x88, 0x04, 0x51, 0x48, 0xf5, 0xea, 0x7a,
+	0x89, 0xeb, 0xa5, 0x64, 0xde, 0x15, 0xe7, 0x18, 0x44, 0x2c, 0x1e, 0xb8, 0x0e, 0x77, 0x62, 0x85,
+	0x3c, 0x59, 0xf3, 0x39, 0xac, 0x1e, 0xbb, 0x63, 0x6f, 0xe8, 0x32, 0x9c, 0xbe, 0x03, 0x7c, 0x9c,
+	0xf4, 0x51, 0x4b, 0xc1, 0xfc, 0x4d, 0x83, 0xb5, 0x9c, 0xbb, 0xba, 0x21, 0x0d, 0x98, 0x77, 0x07,
+	0xcc, 0x9b, 0xca, 0x2f, 0x63, 0xc1, 0x56, 0x52, 0xfa, 0x9c, 0x66, 0x6f, 0x71, 0x4e, 0xe9, 0x6b,
+	0xe4, 0xf9, 0x7a, 0x29, 0x73, 0x8d, 0xda, 0x62, 0x52, 0x82, 0x5d, 0x1a, 0xf8, 0x0a, 0x66, 0x25,
+	0x09, 0xf4, 0x09, 0x09, 0x88, 0x82, 0x59, 0x0a, 0xe6, 0x56, 0xae, 0x5b, 0x1a, 0x4f, 0xd7, 0x80,
+	0x79, 0x31, 0x10, 0xd5, 0x35, 0x09, 0x98, 0x94, 0xcc, 0x63, 0x68, 0xe4, 0x03, 0xd4, 0x7c, 0x5f,
+	0x43, 0x85, 0x60, 0x1a, 0x8d, 0x99, 0x0c, 0xa9, 0xed, 0x98, 0x99, 0x39, 0x0a, 0x41, 0xb1, 0xe3,
+	0x10, 0xf3, 0x0b, 0x68, 0xf4, 0x98, 0x4b, 0x58, 0x77, 0x37, 0x62, 0x67, 0xf9, 0x9d, 0x92, 0xec,
+	0x06, 0x2d, 0xbb, 0x1b, 0xcc, 0x1f, 0xe0, 0xde, 0xa5, 0x28, 0xd5, 0xce, 0x33, 0x58, 0x76, 0x23,
+	0x76, 0x16, 0x10, 0xef, 0x17, 0x97, 0x79, 0x81, 0xef, 0x44, 0x64, 0xac, 0xe2, 0xeb, 0x19, 0xc3,
+	0x2b, 0x32, 0xe6, 0xe0, 0x50, 0xe6, 0xb2, 0x64, 0x01, 0x08, 0xc1, 0x74, 0xe1, 0xfe, 0x5e, 0x30,
+	0x09, 0xc7, 0x98, 0xe1, 0xff, 0xd4, 0x16, 0xbf, 0xfd, 0x83, 0x60, 0x18, 0x67, 0x13, 0xbf, 0x2f,
+	0x4a, 0x94, 0xd2, 0x25, 0x1a, 0xb0, 0x7a, 0xe8, 0x51, 0x76, 0xa4, 0x22, 0x63, 0xf8, 0xcd, 0xef,
+	0x60, 0x2d, 0xa7, 0x57, 0x63, 0xbd, 0x80, 0x6a, 0x5c, 0x26, 0xc6, 0xd9, 0xc8, 0xe0, 0x2c, 0x9a,
+	0x8c, 0xe3, 0xec, 0x0b, 0x67, 0x93, 0xc0, 0x52, 0xc6, 0x96, 0x7c, 0xa3, 0x5a, 0xea, 0x1b, 0xdd,
+	0x80, 0xc5, 0xa1, 0x47, 0xc3, 0xb1, 0x7b, 0xee, 0x08, 0x9b, 0x9c, 0xa0, 0xa6, 0x74, 0x1d, 0xee,
+	0xb2, 0x09, 0xe5, 0x77, 0xe3, 0xe0, 0x83, 0x98, 0xe3, 0xce, 0x4e, 0xe3, 0x72, 0xf1, 0xfd, 0x71,
+	0xf0, 0xc1, 0x16, 0x3e, 0x7c, 0x7d, 0x9e, 0xb8, 0x6c, 0x70, 0xd6, 0xc3, 0x94, 0x7a, 0x41, 0xb2,
+	0x3e, 0x7f, 0xd5, 0x60, 0x51, 0xa9, 0xac, 0x29, 0xf6, 0x19, 0xda, 0x86, 0xb2, 0x58, 0x62, 0x9a,
+	0xc8, 0xf9, 0x20, 0x93, 0x33, 0xed, 0xc8, 0x17, 0x9b, 0x2d, 0x5c, 0x2f, 0x2d, 0xdc, 0xd9, 0x9b,
+	0x16, 0x6e, 0x29, 0xbf, 0x70, 0x75, 0xa8, 0x4c, 0x30, 0xa5, 0xee, 0x28, 0x5e, 0x9e, 0xb1, 0x68,
+	0x06, 0xb0, 0x6a, 0x7d, 0x1c, 0x9c, 0xb9, 0xfe, 0x28, 0xfb, 0xc9, 0x3f, 0x82, 0x25, 0x1a, 0x9d,
+	0xfe, 0x84, 0x07, 0x2c, 0xb3, 0xe5, 0x17, 0x95, 0x52, 0x56, 0x35, 0x60, 0xc1, 0x8d, 0x86, 0x1e,
+	0xf6, 0x07, 0x31, 0x7c, 0x89, 0xcc, 0xbf, 0x2a, 0x3a, 0x08, 0x42, 0x4c, 0xf5, 0x92, 0xfc, 0xaa,
+	0xa4, 0x64, 0xfe, 0xa1, 0xc1, 0x5a, 0xae, 0xe2, 0xed, 0x79, 0x65, 0x13, 0x96, 0x3d, 0x4a, 0x23,
+	0x3c, 0x74, 0x52, 0x74, 0x20, 0x2b, 0xdf, 0x95, 0x86, 0x7e, 0x42, 0x0a, 0x59, 0xce, 0x28, 0xe5,
+	0x39, 0x23, 0xbb, 0x5b, 0xca, 0x05, 0xbb, 0x45, 0xb5, 0x3f, 0x97, 0x6e, 0x7f, 0xf3, 0x0d, 0x54,
+	0x93, 0x93, 0x47, 0x06, 0x34, 0x0e, 0xbb, 0x07, 0xed, 0x8e, 0xb3, 0x7f, 0xd8, 0x3d, 0x71, 0x5e,
+	0x75, 0x7a, 0x47, 0xd6, 0x5e, 0x7b, 0xbf, 0x6d, 0xb5, 0xea, 0x33, 0xe8, 0x1e, 0xac, 0xa4, 0x6c,
+	0x47, 0xbb, 0xbd, 0xde, 0x49, 0xd7, 0x6e, 0xd5, 0xb5, 0x9c, 0xc1, 0xb6, 0x5a, 0x6d, 0xdb, 0xda,
+	0xeb, 0xd7, 0x67, 0x37, 0xff, 0xd1, 0xa0, 0x9e, 0xbf, 0x01, 0xc8, 0x84, 0x87, 0x3d, 0xab, 0xd7,
+	0x6b, 0x77, 0x3b, 0x8e, 0x75, 0x6c, 0x75, 0xfa, 0x4e, 0xff, 0xcd, 0x91, 0x95, 0x2b, 0xf5, 0x04,
+	0xcc, 0x02, 0x9f, 0x7e, 0xf7, 0xa5, 0xd5, 0x71, 0x6c, 0x6b, 0xdf, 0xb6, 0x7a, 0xdf, 0x5a, 0xbc,
+	0xf2, 0x27, 0xb0, 0x51, 0xe0, 0x67, 0xbd, 0x3e, 0x6a, 0xdb, 0x6f, 0x9c, 0x93, 0x5d, 0xbb, 0xd3,
+	0xee, 0x1c, 0xd4, 0x67, 0xd1, 0x43, 0x30, 0xae, 0x72, 0xb3, 0x5a, 0xf5, 0xd2, 0x15, 0x76, 0xdb,
+	0x3a, 0xee, 0xbe, 0xb4, 0x5a, 0xf5, 0x32, 0x7a, 0x0c, 0xeb, 0x05, 0xf6, 0xfd, 0xae, 0xbd, 0x67,
+	0xb5, 0x9c, 0xc3, 0xee, 0x41, 0xf7, 0x55, 0xbf, 0x3e, 0xb7, 0xf3, 0x7b, 0x05, 0x6a, 0x7c, 0xd3,
+	0xf4, 0x30, 0x99, 0x7a, 0x03, 0x8c, 0x3a, 0xb0, 0x98, 0x7e, 0xe1, 0xa0, 0xf5, 0x9b, 0x1e, 0x3f,
+	0x46, 0xc1, 0x32, 0x48, 0x98, 0x6c, 0x86, 0xe7, 0x4b, 0x3f, 0x67, 0x72, 0xf9, 0x0a, 0x5e, 0x3a,
+	0x37, 0xe4, 0x6b, 0x01, 0x5c, 0xbc, 0x19, 0xd0, 0xc3, 0x8c, 0xef, 0xa5, 0xc7, 0x84, 0x51, 0x48,
+	0x6c, 0xe6, 0x0c, 0xda, 0x83, 0x79, 0xc9, 0xb9, 0xe8, 0x52, 0xb5, 0x0b, 0x66, 0x36, 0xfe, 0x57,
+	0x68, 0x4b, 0x5a, 0x79, 0x0d, 0x4b, 0x19, 0x8a, 0x41, 0x1b, 0xd7, 0xd1, 0x8f, 0x4c, 0x79, 0x0b,
+	0x86, 0x32, 0x67, 0xd0, 0xf7, 0x70, 0x27, 0x63, 0xa2, 0xe8, 0x9a, 0xb8, 0x78, 0x83, 0x1b, 0x8f,
+	0xae, 0xf5, 0x49, 0x92, 0xff, 0x08, 0x77, 0x73, 0x0c, 0x86, 0xb2, 0x91, 0xc5, 0xac, 0x68, 0x3c,
+	0xbe, 0xde, 0x29, 0xc9, 0xff, 0x16, 0xd0, 0x65, 0x0e, 0x43, 0x4f, 0x32, 0xd1, 0x57, 0x92, 0xdc,
+	0x0d, 0xa7, 0xff, 0x1a, 0x96, 0x32, 0x24, 0x95, 0x83, 0xbc, 0x88, 0xd8, 0x0c, 0xf3, 0x3a, 0x97,
+	0x24, 0x73, 0x17, 0x16, 0xd3, 0xbc, 0x91, 0xbb, 0xa7, 0x05, 0x94, 0x62, 0xdc, 0xbf, 0x92, 0x33,
+	0xcc, 0x99, 0xcf, 0x34, 0xde, 0x6a, 0x66, 0xbf, 0xe6, 0x5a, 0x2d, 0xda, 0xf6, 0x86, 0x79, 0x9d,
+	0x4b, 0xdc, 0xea, 0x37, 0xcd, 0xb7, 0xcf, 0x47, 0x1e, 0x3b, 0x8b, 0x4e, 0x9b, 0x83, 0x60, 0xb2,
+	0x35, 0x09, 0x98, 0xe7, 0x26, 0x7f, 0x9d, 0x3e, 0x1d, 0x91, 0x70, 0xb0, 0x35, 0xdd, 0xfe, 0x2a,
+	0x56, 0x4c, 0xb7, 0x4f, 0xe7, 0xc5, 0xdf, 0xa9, 0xcf, 0xff, 0x1d, 0x00, 0x72, 0xe0, 0x7b, 0x6c,
+	0x63, 0x0d, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// AuthServiceClient is the client API for AuthService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type AuthServiceClient interface {
+	AttemptLogin(ctx context.Context, in *AttemptLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error)
+	Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error)
+	ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error)
+	ValidateTokens(ctx context.Context, in *ValidateTokensRequest, opts ...grpc.CallOption) (*ValidateTokensResponse, error)
+	StartOAuthLogin(ctx context.Context, in *StartOAuthLoginRequest, opts ...grpc.CallOption) (*StartOAuthLoginResponse, error)
+	CompleteOAuthLogin(ctx context.Context, in *CompleteOAuthLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersResponse, error)
+	WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (AuthService_WatchSessionClient, error)
+	ExchangeToken(ctx context.Context, in *ExchangeTokenRequest, opts ...grpc.CallOption) (*ExchangeTokenResponse, error)
+}
+
+type authServiceClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewAuthServiceClient(cc *grpc.ClientConn) AuthServiceClient {
+	return &authServiceClient{cc}
+}
+
+func (c *authServiceClient) AttemptLogin(ctx context.Context, in *AttemptLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/AttemptLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) RefreshToken(ctx context.Context, in *RefreshTokenRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/RefreshToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) GetProfile(ctx context.Context, in *GetProfileRequest, opts ...grpc.CallOption) (*Profile, error) {
+	out := new(Profile)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/GetProfile", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) Logout(ctx context.Context, in *LogoutRequest, opts ...grpc.CallOption) (*LogoutResponse, error) {
+	out := new(LogoutResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/Logout", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ValidateToken(ctx context.Context, in *ValidateTokenRequest, opts ...grpc.CallOption) (*ValidateTokenResponse, error) {
+	out := new(ValidateTokenResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/ValidateToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ValidateTokens(ctx context.Context, in *ValidateTokensRequest, opts ...grpc.CallOption) (*ValidateTokensResponse, error) {
+	out := new(ValidateTokensResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/ValidateTokens", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) StartOAuthLogin(ctx context.Context, in *StartOAuthLoginRequest, opts ...grpc.CallOption) (*StartOAuthLoginResponse, error) {
+	out := new(StartOAuthLoginResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/StartOAuthLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) CompleteOAuthLogin(ctx context.Context, in *CompleteOAuthLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/CompleteOAuthLogin", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) ListProviders(ctx context.Context, in *ListProvidersRequest, opts ...grpc.CallOption) (*ListProvidersResponse, error) {
+	out := new(ListProvidersResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/ListProviders", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *authServiceClient) WatchSession(ctx context.Context, in *WatchSessionRequest, opts ...grpc.CallOption) (AuthService_WatchSessionClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_AuthService_serviceDesc.Streams[0], "/loginsrv.v1.AuthService/WatchSession", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &authServiceWatchSessionClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type AuthService_WatchSessionClient interface {
+	Recv() (*SessionEvent, error)
+	grpc.ClientStream
+}
+
+type authServiceWatchSessionClient struct {
+	grpc.ClientStream
+}
+
+func (x *authServiceWatchSessionClient) Recv() (*SessionEvent, error) {
+	m := new(SessionEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *authServiceClient) ExchangeToken(ctx context.Context, in *ExchangeTokenRequest, opts ...grpc.CallOption) (*ExchangeTokenResponse, error) {
+	out := new(ExchangeTokenResponse)
+	err := c.cc.Invoke(ctx, "/loginsrv.v1.AuthService/ExchangeToken", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AuthServiceServer is the server API for AuthService service.
+type AuthServiceServer interface {
+	AttemptLogin(context.Context, *AttemptLoginRequest) (*LoginResponse, error)
+	RefreshToken(context.Context, *RefreshTokenRequest) (*LoginResponse, error)
+	GetProfile(context.Context, *GetProfileRequest) (*Profile, error)
+	Logout(context.Context, *LogoutRequest) (*LogoutResponse, error)
+	ValidateToken(context.Context, *ValidateTokenRequest) (*ValidateTokenResponse, error)
+	ValidateTokens(context.Context, *ValidateTokensRequest) (*ValidateTokensResponse, error)
+	StartOAuthLogin(context.Context, *StartOAuthLoginRequest) (*StartOAuthLoginResponse, error)
+	CompleteOAuthLogin(context.Context, *CompleteOAuthLoginRequest) (*LoginResponse, error)
+	ListProviders(context.Context, *ListProvidersRequest) (*ListProvidersResponse, error)
+	WatchSession(*WatchSessionRequest, AuthService_WatchSessionServer) error
+	ExchangeToken(context.Context, *ExchangeTokenRequest) (*ExchangeTokenResponse, error)
+}
+
+// UnimplementedAuthServiceServer can be embedded to have forward compatible implementations.
+type UnimplementedAuthServiceServer struct {
+}
+
+func (*UnimplementedAuthServiceServer) AttemptLogin(ctx context.Context, req *AttemptLoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AttemptLogin not implemented")
+}
+func (*UnimplementedAuthServiceServer) RefreshToken(ctx context.Context, req *RefreshTokenRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RefreshToken not implemented")
+}
+func (*UnimplementedAuthServiceServer) GetProfile(ctx context.Context, req *GetProfileRequest) (*Profile, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetProfile not implemented")
+}
+func (*UnimplementedAuthServiceServer) Logout(ctx context.Context, req *LogoutRequest) (*LogoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Logout not implemented")
+}
+func (*UnimplementedAuthServiceServer) ValidateToken(ctx context.Context, req *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateToken not implemented")
+}
+func (*UnimplementedAuthServiceServer) ValidateTokens(ctx context.Context, req *ValidateTokensRequest) (*ValidateTokensResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ValidateTokens not implemented")
+}
+func (*UnimplementedAuthServiceServer) StartOAuthLogin(ctx context.Context, req *StartOAuthLoginRequest) (*StartOAuthLoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartOAuthLogin not implemented")
+}
+func (*UnimplementedAuthServiceServer) CompleteOAuthLogin(ctx context.Context, req *CompleteOAuthLoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteOAuthLogin not implemented")
+}
+func (*UnimplementedAuthServiceServer) ListProviders(ctx context.Context, req *ListProvidersRequest) (*ListProvidersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProviders not implemented")
+}
+func (*UnimplementedAuthServiceServer) WatchSession(req *WatchSessionRequest, srv AuthService_WatchSessionServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchSession not implemented")
+}
+func (*UnimplementedAuthServiceServer) ExchangeToken(ctx context.Context, req *ExchangeTokenRequest) (*ExchangeTokenResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExchangeToken not implemented")
+}
+
+func RegisterAuthServiceServer(s *grpc.Server, srv AuthServiceServer) {
+	s.RegisterService(&_AuthService_serviceDesc, srv)
+}
+
+func _AuthService_AttemptLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AttemptLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).AttemptLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/AttemptLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).AttemptLogin(ctx, req.(*AttemptLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_RefreshToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).RefreshToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/RefreshToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).RefreshToken(ctx, req.(*RefreshTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_GetProfile_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProfileRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).GetProfile(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/GetProfile",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).GetProfile(ctx, req.(*GetProfileRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_Logout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).Logout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/Logout",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).Logout(ctx, req.(*LogoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ValidateToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ValidateToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/ValidateToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ValidateToken(ctx, req.(*ValidateTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ValidateTokens_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ValidateTokensRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ValidateTokens(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/ValidateTokens",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ValidateTokens(ctx, req.(*ValidateTokensRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_StartOAuthLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartOAuthLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).StartOAuthLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/StartOAuthLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).StartOAuthLogin(ctx, req.(*StartOAuthLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_CompleteOAuthLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteOAuthLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).CompleteOAuthLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/CompleteOAuthLogin",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).CompleteOAuthLogin(ctx, req.(*CompleteOAuthLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_ListProviders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProvidersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ListProviders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/ListProviders",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ListProviders(ctx, req.(*ListProvidersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AuthService_WatchSession_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchSessionRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AuthServiceServer).WatchSession(m, &authServiceWatchSessionServer{stream})
+}
+
+type AuthService_WatchSessionServer interface {
+	Send(*SessionEvent) error
+	grpc.ServerStream
+}
+
+type authServiceWatchSessionServer struct {
+	grpc.ServerStream
+}
+
+func (x *authServiceWatchSessionServer) Send(m *SessionEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _AuthService_ExchangeToken_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExchangeTokenRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AuthServiceServer).ExchangeToken(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/loginsrv.v1.AuthService/ExchangeToken",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AuthServiceServer).ExchangeToken(ctx, req.(*ExchangeTokenRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _AuthService_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "loginsrv.v1.AuthService",
+	HandlerType: (*AuthServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AttemptLogin",
+			Handler:    _AuthService_AttemptLogin_Handler,
+		},
+		{
+			MethodName: "RefreshToken",
+			Handler:    _AuthService_RefreshToken_Handler,
+		},
+		{
+			MethodName: "GetProfile",
+			Handler:    _AuthService_GetProfile_Handler,
+		},
+		{
+			MethodName: "Logout",
+			Handler:    _AuthService_Logout_Handler,
+		},
+		{
+			MethodName: "ValidateToken",
+			Handler:    _AuthService_ValidateToken_Handler,
+		},
+		{
+			MethodName: "ValidateTokens",
+			Handler:    _AuthService_ValidateTokens_Handler,
+		},
+		{
+			MethodName: "StartOAuthLogin",
+			Handler:    _AuthService_StartOAuthLogin_Handler,
+		},
+		{
+			MethodName: "CompleteOAuthLogin",
+			Handler:    _AuthService_CompleteOAuthLogin_Handler,
+		},
+		{
+			MethodName: "ListProviders",
+			Handler:    _AuthService_ListProviders_Handler,
+		},
+		{
+			MethodName: "ExchangeToken",
+			Handler:    _AuthService_ExchangeToken_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchSession",
+			Handler:       _AuthService_WatchSession_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v1/loginsrv.proto",
+}
@@ -0,0 +1,154 @@
+package loginsrvv1
+
+import (
+	"context"
+
+	grpc_auth "github.com/grpc-ecosystem/go-grpc-middleware/auth"
+	loginsrv_grpc "github.com/motia/loginsrv-grpc"
+)
+
+// authServiceServer serves the v1 API with an implementation of the unversioned Auth service
+type authServiceServer struct {
+	srv loginsrv_grpc.AuthServer
+}
+
+// authServiceServerWithOverride keeps the grpc_auth override of the wrapped server,
+// so the login routes stay public on the v1 API
+type authServiceServerWithOverride struct {
+	*authServiceServer
+	grpc_auth.ServiceAuthFuncOverride
+}
+
+// NewAuthServiceServer serves the v1 API with an unversioned Auth server,
+// usually a *loginsrv_grpc.LoginSrvServer, both APIs can be registered side by side
+func NewAuthServiceServer(srv loginsrv_grpc.AuthServer) AuthServiceServer {
+	adapter := &authServiceServer{srv: srv}
+	if override, ok := srv.(grpc_auth.ServiceAuthFuncOverride); ok {
+		return &authServiceServerWithOverride{adapter, override}
+	}
+	return adapter
+}
+
+func (s *authServiceServer) AttemptLogin(ctx context.Context, request *AttemptLoginRequest) (*LoginResponse, error) {
+	reply, err := s.srv.AttemptLogin(ctx, &loginsrv_grpc.LoginRequest{
+		Username: request.Username,
+		Password: request.Password,
+		Provider: request.Provider,
+		Extra:    request.Extra,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromLegacyLoginReply(reply), nil
+}
+
+func (s *authServiceServer) RefreshToken(ctx context.Context, request *RefreshTokenRequest) (*LoginResponse, error) {
+	reply, err := s.srv.RefreshToken(ctx, &loginsrv_grpc.RefreshRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return fromLegacyLoginReply(reply), nil
+}
+
+func (s *authServiceServer) GetProfile(ctx context.Context, request *GetProfileRequest) (*Profile, error) {
+	profile, err := s.srv.GetProfile(ctx, &loginsrv_grpc.ProfileRequest{})
+	if err != nil {
+		return nil, err
+	}
+	return fromLegacyProfile(profile), nil
+}
+
+func (s *authServiceServer) Logout(ctx context.Context, request *LogoutRequest) (*LogoutResponse, error) {
+	if _, err := s.srv.Logout(ctx, &loginsrv_grpc.LogoutRequest{}); err != nil {
+		return nil, err
+	}
+	return &LogoutResponse{}, nil
+}
+
+func (s *authServiceServer) ValidateToken(ctx context.Context, request *ValidateTokenRequest) (*ValidateTokenResponse, error) {
+	reply, err := s.srv.ValidateToken(ctx, &loginsrv_grpc.ValidateTokenRequest{Token: request.Token})
+	if err != nil {
+		return nil, err
+	}
+	return fromLegacyValidateTokenReply(reply), nil
+}
+
+func (s *authServiceServer) ValidateTokens(ctx context.Context, request *ValidateTokensRequest) (*ValidateTokensResponse, error) {
+	reply, err := s.srv.ValidateTokens(ctx, &loginsrv_grpc.ValidateTokensRequest{Tokens: request.Tokens})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ValidateTokensResponse{}
+	for _, result := range reply.Results {
+		response.Results = append(response.Results, fromLegacyValidateTokenReply(result))
+	}
+	return response, nil
+}
+
+func (s *authServiceServer) StartOAuthLogin(ctx context.Context, request *StartOAuthLoginRequest) (*StartOAuthLoginResponse, error) {
+	reply, err := s.srv.StartOAuthLogin(ctx, &loginsrv_grpc.StartOAuthLoginRequest{Provider: request.Provider})
+	if err != nil {
+		return nil, err
+	}
+	return &StartOAuthLoginResponse{
+		AuthorizationUrl: reply.AuthorizationUrl,
+		State:            reply.State,
+	}, nil
+}
+
+func (s *authServiceServer) CompleteOAuthLogin(ctx context.Context, request *CompleteOAuthLoginRequest) (*LoginResponse, error) {
+	reply, err := s.srv.CompleteOAuthLogin(ctx, &loginsrv_grpc.CompleteOAuthLoginRequest{
+		Provider: request.Provider,
+		Code:     request.Code,
+		State:    request.State,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromLegacyLoginReply(reply), nil
+}
+
+func (s *authServiceServer) ListProviders(ctx context.Context, request *ListProvidersRequest) (*ListProvidersResponse, error) {
+	reply, err := s.srv.ListProviders(ctx, &loginsrv_grpc.ListProvidersRequest{})
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListProvidersResponse{}
+	for _, provider := range reply.Providers {
+		response.Providers = append(response.Providers, fromLegacyProvider(provider))
+	}
+	return response, nil
+}
+
+func (s *authServiceServer) ExchangeToken(ctx context.Context, request *ExchangeTokenRequest) (*ExchangeTokenResponse, error) {
+	reply, err := s.srv.ExchangeToken(ctx, &loginsrv_grpc.ExchangeTokenRequest{
+		SubjectToken: request.SubjectToken,
+		Audience:     request.Audience,
+		Scopes:       request.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &ExchangeTokenResponse{
+		AccessToken:     reply.AccessToken,
+		IssuedTokenType: reply.IssuedTokenType,
+		TokenType:       reply.TokenType,
+		ExpiresIn:       reply.ExpiresIn,
+		Scopes:          reply.Scopes,
+	}, nil
+}
+
+func (s *authServiceServer) WatchSession(request *WatchSessionRequest, stream AuthService_WatchSessionServer) error {
+	return s.srv.WatchSession(&loginsrv_grpc.WatchSessionRequest{}, &legacyWatchSessionServer{stream})
+}
+
+// legacyWatchSessionServer converts the events sent by the unversioned server
+type legacyWatchSessionServer struct {
+	AuthService_WatchSessionServer
+}
+
+func (s *legacyWatchSessionServer) Send(event *loginsrv_grpc.SessionEvent) error {
+	return s.AuthService_WatchSessionServer.Send(fromLegacySessionEvent(event))
+}
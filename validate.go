@@ -0,0 +1,242 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// IntrospectionPolicy decides whether the caller may use validateToken
+// the caller profile is nil when the RPC carries no valid token,
+// returning an error rejects the RPC with it
+type IntrospectionPolicy func(ctx context.Context, caller *Profile) error
+
+// WithIntrospectionPolicy protects validateToken, without a policy the RPC is always rejected
+func WithIntrospectionPolicy(policy IntrospectionPolicy) Option {
+	return func(s *LoginSrvServer) {
+		s.introspectionPolicy = policy
+	}
+}
+
+// WithJWTSecret checks the tokens signed by loginsrv with the HS256, HS384 or HS512 secret
+// on the server instead of asking loginsrv, like the -jwt-secret flag of loginsrv
+func WithJWTSecret(secret []byte) Option {
+	return func(s *LoginSrvServer) {
+		s.jwtSecret = secret
+	}
+}
+
+// WithValidationConcurrency bounds the tokens validateTokens checks at once, the default is 8
+func WithValidationConcurrency(n int) Option {
+	return func(s *LoginSrvServer) {
+		if n < 1 {
+			n = 1
+		}
+		s.validationConcurrency = n
+	}
+}
+
+// WithValidationCache keeps the profiles of valid tokens for the ttl, or until their expiry,
+// so Authenticate and the other validations skip loginsrv for tokens already seen
+// revoked tokens are still rejected, profile changes upstream show up after the ttl
+func WithValidationCache(ttl time.Duration) Option {
+	return func(s *LoginSrvServer) {
+		s.profiles = newProfileCache(ttl)
+	}
+}
+
+// TrustedGroups allows the callers authenticated with a token of one of the groups
+func TrustedGroups(groups ...string) IntrospectionPolicy {
+	return func(ctx context.Context, caller *Profile) error {
+		if caller == nil {
+			return grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
+		}
+		for _, group := range caller.Groups {
+			for _, trusted := range groups {
+				if group == trusted {
+					return nil
+				}
+			}
+		}
+		return grpc.Errorf(codes.PermissionDenied, "caller is not trusted")
+	}
+}
+
+// ValidateToken tells trusted callers whether a token is active and whose it is
+// it runs the same checks as Authenticate
+func (s *LoginSrvServer) ValidateToken(ctx context.Context, request *ValidateTokenRequest) (*ValidateTokenReply, error) {
+	if err := s.authorizeIntrospection(ctx); err != nil {
+		return nil, err
+	}
+	return s.introspect(ctx, request.Token)
+}
+
+// ValidateTokens checks a batch of tokens, each distinct token is checked once
+// and the checks run in parallel, a token which could not be checked gets an error result
+func (s *LoginSrvServer) ValidateTokens(ctx context.Context, request *ValidateTokensRequest) (*ValidateTokensReply, error) {
+	if err := s.authorizeIntrospection(ctx); err != nil {
+		return nil, err
+	}
+
+	results := map[string]*ValidateTokenReply{}
+	distinct := []string{}
+	for _, token := range request.Tokens {
+		if _, ok := results[token]; !ok {
+			results[token] = nil
+			distinct = append(distinct, token)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, s.validationConcurrency)
+	for _, token := range distinct {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return nil, grpc.Errorf(codes.Canceled, "%v", ctx.Err())
+		}
+
+		wg.Add(1)
+		go func(token string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			reply, err := s.introspect(ctx, token)
+			if err != nil {
+				reply = &ValidateTokenReply{Error: checkError(err)}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[token] = reply
+		}(token)
+	}
+	wg.Wait()
+
+	reply := &ValidateTokensReply{Results: make([]*ValidateTokenReply, len(request.Tokens))}
+	for i, token := range request.Tokens {
+		reply.Results[i] = results[token]
+	}
+	return reply, nil
+}
+
+// introspect describes the state of a token
+// only the errors not caused by the token itself are returned
+func (s *LoginSrvServer) introspect(ctx context.Context, token string) (*ValidateTokenReply, error) {
+	token, err := s.resolveToken(ctx, token)
+	var profile *Profile
+	if err == nil {
+		profile, err = s.validateToken(token)
+	}
+	if err != nil {
+		if status.Code(err) != codes.Unauthenticated {
+			return nil, err
+		}
+		return &ValidateTokenReply{
+			Active: false,
+			Reason: status.Convert(err).Message(),
+		}, nil
+	}
+
+	reply := &ValidateTokenReply{
+		Active:  true,
+		Profile: profile,
+	}
+	if profile.Expiry != 0 {
+		reply.ExpiresIn = int64(time.Until(time.Unix(profile.Expiry, 0)).Seconds())
+	}
+	return reply, nil
+}
+
+// checkError describes why a token could not be checked, with the code when there is no message
+func checkError(err error) string {
+	st := status.Convert(err)
+	if st.Message() == "" {
+		return st.Code().String()
+	}
+	return st.Message()
+}
+
+func (s *LoginSrvServer) authorizeIntrospection(ctx context.Context) error {
+	if s.introspectionPolicy == nil {
+		return grpc.Errorf(codes.PermissionDenied, "introspection is disabled")
+	}
+
+	var caller *Profile
+	if token := s.tokenFromContext(ctx); token != nil {
+		caller, _ = s.validateToken(*token)
+	}
+	return s.introspectionPolicy(ctx, caller)
+}
+
+// validateToken returns the profile of an active token
+// it fails with Unauthenticated when the token is not active
+func (s *LoginSrvServer) validateToken(token string) (*Profile, error) {
+	if len(token) == 0 {
+		return nil, grpc.Errorf(codes.Unauthenticated, "missing token")
+	}
+	if s.revoked.has(token) {
+		return nil, grpc.Errorf(codes.Unauthenticated, "token revoked")
+	}
+
+	profile, err := s.lookupProfile(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if profile.Expiry != 0 && !time.Now().Before(time.Unix(profile.Expiry, 0)) {
+		return nil, grpc.Errorf(codes.Unauthenticated, "token expired")
+	}
+	return profile, nil
+}
+
+// lookupProfile checks the signature locally when the secret is known, otherwise asks loginsrv
+// concurrent lookups of the same token share a single loginsrv request,
+// successful ones are kept by the validation cache
+func (s *LoginSrvServer) lookupProfile(token string) (*Profile, error) {
+	if s.jwtSecret != nil {
+		claims, err := verifyToken(token, s.jwtSecret)
+		if err != nil {
+			return nil, grpc.Errorf(codes.Unauthenticated, "invalid token")
+		}
+		return claims.Profile(), nil
+	}
+
+	if s.profiles != nil {
+		if profile, ok := s.profiles.get(token); ok {
+			return profile, nil
+		}
+	}
+
+	profile, err := s.lookups.do(token, func() (*Profile, error) {
+		return s.fetchProfile(token)
+	})
+	switch status.Code(err) {
+	case codes.OK:
+		if s.profiles != nil {
+			s.profiles.add(token, profile)
+		}
+		return profile, nil
+	case codes.PermissionDenied, codes.InvalidArgument:
+		return nil, grpc.Errorf(codes.Unauthenticated, "invalid token")
+	default:
+		return nil, err
+	}
+}
+
+type profileKey struct{}
+
+func withProfile(ctx context.Context, profile *Profile) context.Context {
+	return context.WithValue(ctx, profileKey{}, profile)
+}
+
+// ProfileFromContext returns the profile of the token checked by Authenticate
+func ProfileFromContext(ctx context.Context) (*Profile, bool) {
+	profile, ok := ctx.Value(profileKey{}).(*Profile)
+	return profile, ok
+}
@@ -0,0 +1,213 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	md "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestValidateToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithIntrospectionPolicy(TrustedGroups("dev")))
+	caller := &contextWithAuthorizationStub{authToken: obtainTokenOrFail(t, srv)}
+	token := obtainTokenOrFail(t, srv)
+
+	reply, err := srv.ValidateToken(caller, &ValidateTokenRequest{Token: token})
+	if err != nil {
+		t.Fatal("ValidateToken failed", err)
+	}
+	if !reply.Active || reply.Profile.GetSub() != "bob" {
+		t.Errorf("Token should be active for bob but got %v", reply)
+	}
+	if reply.ExpiresIn <= 0 {
+		t.Error("Remaining lifetime should be positive")
+	}
+
+	srv.revoked.revoke(token)
+	for token, reason := range map[string]string{token: "token revoked", "garbage": "invalid token", "": "missing token"} {
+		reply, err = srv.ValidateToken(caller, &ValidateTokenRequest{Token: token})
+		if err != nil {
+			t.Fatal("ValidateToken failed", err)
+		}
+		if reply.Active || reply.Reason != reason {
+			t.Errorf("Expected inactive token because of %q but got %v", reason, reply)
+		}
+	}
+}
+
+func TestValidateTokenRequiresTrustedCaller(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	token := obtainTokenOrFail(t, NewLoginSrvServer(upstream.URL))
+	caller := &contextWithAuthorizationStub{authToken: token}
+
+	srv := NewLoginSrvServer(upstream.URL)
+	if _, err := srv.ValidateToken(caller, &ValidateTokenRequest{Token: token}); status.Code(err) != codes.PermissionDenied {
+		t.Error("Introspection should be disabled without policy", err)
+	}
+
+	srv = NewLoginSrvServer(upstream.URL, WithIntrospectionPolicy(TrustedGroups("admin")))
+	if _, err := srv.ValidateToken(caller, &ValidateTokenRequest{Token: token}); status.Code(err) != codes.PermissionDenied {
+		t.Error("Caller outside of the trusted groups should be rejected", err)
+	}
+
+	anonymous := &contextWithAuthorizationStub{}
+	if _, err := srv.ValidateToken(anonymous, &ValidateTokenRequest{Token: token}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Anonymous caller should be rejected", err)
+	}
+}
+
+func TestAuthenticateStoresProfile(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	ctx := &contextWithAuthorizationStub{authToken: obtainTokenOrFail(t, srv)}
+
+	authenticated, err := srv.Authenticate(ctx)
+	if err != nil {
+		t.Fatal("Authenticate failed", err)
+	}
+	profile, ok := ProfileFromContext(authenticated)
+	if !ok || profile.Sub != "bob" {
+		t.Error("Profile of bob should be in the context")
+	}
+
+	if _, err := srv.Authenticate(&contextWithAuthorizationStub{authToken: "garbage"}); status.Code(err) != codes.Unauthenticated {
+		t.Error("Invalid token should not authenticate", err)
+	}
+}
+
+func TestValidateTokensDeduplicatesLookups(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithIntrospectionPolicy(TrustedGroups("dev")))
+	caller := &contextWithAuthorizationStub{authToken: obtainTokenOrFail(t, srv)}
+	token := obtainTokenOrFail(t, srv)
+	upstream.resetLookups()
+
+	reply, err := srv.ValidateTokens(caller, &ValidateTokensRequest{Tokens: []string{token, "garbage", token, token}})
+	if err != nil {
+		t.Fatal("ValidateTokens failed", err)
+	}
+
+	if len(reply.Results) != 4 {
+		t.Fatalf("Expected a result per token but got %v", reply.Results)
+	}
+	for i, active := range []bool{true, false, true, true} {
+		if reply.Results[i].Active != active {
+			t.Errorf("Result %d should have active=%v but got %v", i, active, reply.Results[i])
+		}
+	}
+	// one lookup for the caller, then one per distinct token
+	if upstream.lookupCount() != 3 {
+		t.Errorf("Expected 3 loginsrv lookups but got %d", upstream.lookupCount())
+	}
+}
+
+func TestValidateTokensReportsErrorsPerToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	release := make(chan struct{})
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(jwtCookieName)
+		if err != nil {
+			upstream.Config.Handler.ServeHTTP(w, r)
+			return
+		}
+		switch cookie.Value {
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "slow":
+			<-release
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			upstream.Config.Handler.ServeHTTP(w, r)
+		}
+	}))
+	defer flaky.Close()
+	srv := NewLoginSrvServer(flaky.URL, WithIntrospectionPolicy(TrustedGroups("dev")), WithValidationConcurrency(1))
+	token := obtainTokenOrFail(t, srv)
+	caller := &contextWithAuthorizationStub{authToken: token}
+
+	reply, err := srv.ValidateTokens(caller, &ValidateTokensRequest{Tokens: []string{token, "broken"}})
+	if err != nil {
+		t.Fatal("ValidateTokens failed", err)
+	}
+	if !reply.Results[0].Active || reply.Results[0].Error != "" {
+		t.Errorf("Valid token should stay active but got %v", reply.Results[0])
+	}
+	if reply.Results[1].Active || reply.Results[1].Error == "" {
+		t.Errorf("Token which could not be checked should carry the error but got %v", reply.Results[1])
+	}
+
+	ctx, cancel := context.WithCancel(md.NewIncomingContext(context.Background(), md.Pairs(AuthTokenMetadataKey, "bearer "+token)))
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+		close(release)
+	}()
+	if _, err := srv.ValidateTokens(ctx, &ValidateTokensRequest{Tokens: []string{"slow", "garbage"}}); status.Code(err) != codes.Canceled {
+		t.Error("Cancelled batch should stop queueing checks", err)
+	}
+}
+
+func TestValidateTokenWithJWTSecretSkipsLoginsrv(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	secret := []byte("my_secret")
+	srv := NewLoginSrvServer(upstream.URL, WithJWTSecret(secret), WithIntrospectionPolicy(TrustedGroups("dev")))
+	token := signedTestToken(secret, map[string]interface{}{"sub": "bob", "groups": []string{"dev"}})
+	caller := &contextWithAuthorizationStub{authToken: token}
+
+	reply, err := srv.ValidateTokens(caller, &ValidateTokensRequest{Tokens: []string{token, obtainTokenOrFail(t, srv)}})
+	if err != nil {
+		t.Fatal("ValidateTokens failed", err)
+	}
+	if !reply.Results[0].Active || reply.Results[1].Active {
+		t.Errorf("Only the token signed with the secret should be active but got %v", reply.Results)
+	}
+	if upstream.lookupCount() != 0 {
+		t.Error("loginsrv should not be asked")
+	}
+}
+
+func TestValidationCache(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL, WithValidationCache(time.Minute))
+	token := obtainTokenOrFail(t, srv)
+
+	upstream.resetLookups()
+	for i := 0; i < 3; i++ {
+		if _, err := srv.validateToken(token); err != nil {
+			t.Fatal("Token should be valid", err)
+		}
+	}
+	if upstream.lookupCount() != 1 {
+		t.Errorf("Expected a single loginsrv lookup but got %d", upstream.lookupCount())
+	}
+	if _, err := srv.validateToken("garbage"); status.Code(err) != codes.Unauthenticated {
+		t.Error("Invalid token should be rejected", err)
+	}
+	if _, err := srv.validateToken("garbage"); status.Code(err) != codes.Unauthenticated || upstream.lookupCount() != 3 {
+		t.Error("Invalid tokens should not be cached", err)
+	}
+
+	srv.revoked.revoke(token)
+	if _, err := srv.validateToken(token); status.Code(err) != codes.Unauthenticated {
+		t.Error("Revoked token should be rejected despite the cache", err)
+	}
+
+	cache := newProfileCache(time.Hour)
+	cache.add("expired", &Profile{Expiry: time.Now().Add(-time.Second).Unix()})
+	if _, ok := cache.get("expired"); ok {
+		t.Error("Entries should not outlive the token expiry")
+	}
+}
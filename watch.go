@@ -0,0 +1,115 @@
+package loginsrv_grpc
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+// WithSessionRefreshBefore sets how long before its expiry watchSession refreshes a token,
+// the default is one minute
+func WithSessionRefreshBefore(d time.Duration) Option {
+	return func(s *LoginSrvServer) {
+		s.refreshBefore = d
+	}
+}
+
+// ForceLogout ends the watched sessions of a subject and revokes their tokens
+func (s *LoginSrvServer) ForceLogout(sub string, reason string) {
+	tokens := s.events.publishSubject(sub, &SessionEvent{
+		Type:    SessionEvent_FORCED_LOGOUT,
+		Message: reason,
+	})
+	for _, token := range tokens {
+		s.revoked.revoke(token)
+	}
+}
+
+// WatchSession streams the events of the caller session until it ends
+// the token is refreshed before it expires and the new one is pushed to the caller
+func (s *LoginSrvServer) WatchSession(request *WatchSessionRequest, stream Auth_WatchSessionServer) error {
+	token := s.tokenFromContext(stream.Context())
+	if token == nil {
+		return grpc.Errorf(codes.Unauthenticated, "Unauthenticated")
+	}
+	profile, err := s.validateToken(*token)
+	if err != nil {
+		return err
+	}
+
+	watcher := s.events.subscribe(profile.Sub, *token)
+	defer s.events.unsubscribe(watcher)
+
+	current := *token
+	refreshable := true
+	for {
+		expiry := tokenExpiry(current)
+		timer, timeout := newExpiryTimer(expiry, refreshable, s.refreshBefore)
+
+		select {
+		case <-stream.Context().Done():
+			timer.Stop()
+			return nil
+
+		case event := <-watcher.events:
+			timer.Stop()
+			sent, err := s.referenceEvent(stream.Context(), event)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(sent); err != nil {
+				return err
+			}
+			switch event.Type {
+			case SessionEvent_TOKEN_REFRESHED:
+				current = event.AccessToken
+				refreshable = true
+			case SessionEvent_REVOKED, SessionEvent_FORCED_LOGOUT:
+				return nil
+			}
+
+		case <-timeout:
+			if !refreshable {
+				return stream.Send(&SessionEvent{
+					Type:      SessionEvent_EXPIRED,
+					ExpiresAt: expiry.Unix(),
+				})
+			}
+			// the refreshed token comes back through the bus
+			if _, err := s.refreshToken(current); err != nil {
+				refreshable = false
+				err = stream.Send(&SessionEvent{
+					Type:      SessionEvent_EXPIRY_WARNING,
+					ExpiresAt: expiry.Unix(),
+					Message:   "token could not be refreshed",
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// newExpiryTimer fires when the token should be refreshed, or once it expired when it cannot be
+// a token without expiry never fires
+func newExpiryTimer(expiry time.Time, refreshable bool, refreshBefore time.Duration) (*time.Timer, <-chan time.Time) {
+	if expiry.IsZero() {
+		timer := time.NewTimer(time.Hour)
+		timer.Stop()
+		return timer, nil
+	}
+
+	wait := time.Until(expiry)
+	if refreshable {
+		// short lived tokens are refreshed halfway instead of right away
+		if wait-refreshBefore > wait/2 {
+			wait -= refreshBefore
+		} else {
+			wait /= 2
+		}
+	}
+	timer := time.NewTimer(wait)
+	return timer, timer.C
+}
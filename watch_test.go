@@ -0,0 +1,143 @@
+package loginsrv_grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	md "google.golang.org/grpc/metadata"
+)
+
+func TestWatchSessionPushesRefreshedToken(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	upstream.lifetime = 3 * time.Second
+	srv := NewLoginSrvServer(upstream.URL, WithSessionRefreshBefore(2*time.Second))
+	token := obtainTokenOrFail(t, srv)
+
+	stream := newWatchStreamStub(token)
+	defer stream.cancel()
+	go srv.WatchSession(&WatchSessionRequest{}, stream)
+
+	event := stream.next(t)
+	if event.Type != SessionEvent_TOKEN_REFRESHED || event.AccessToken == "" || event.AccessToken == token {
+		t.Errorf("Expected a refreshed token but got %v", event)
+	}
+}
+
+func TestWatchSessionEndsOnLogout(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+	token := obtainTokenOrFail(t, srv)
+
+	stream := newWatchStreamStub(token)
+	defer stream.cancel()
+	done := make(chan error)
+	go func() { done <- srv.WatchSession(&WatchSessionRequest{}, stream) }()
+	waitForWatchers(srv, 1)
+
+	if _, err := srv.Logout(&contextWithAuthorizationStub{authToken: token}, &LogoutRequest{}); err != nil {
+		t.Fatal("Logout failed", err)
+	}
+
+	if event := stream.next(t); event.Type != SessionEvent_REVOKED {
+		t.Errorf("Expected a revocation but got %v", event)
+	}
+	if err := <-done; err != nil {
+		t.Error("Stream should end cleanly", err)
+	}
+}
+
+func TestForceLogoutEndsEverySessionOfSubject(t *testing.T) {
+	upstream := newFakeLoginsrv()
+	defer upstream.Close()
+	srv := NewLoginSrvServer(upstream.URL)
+
+	streams := []*watchStreamStub{
+		newWatchStreamStub(obtainTokenOrFail(t, srv)),
+		newWatchStreamStub(obtainTokenOrFail(t, srv)),
+	}
+	for _, stream := range streams {
+		defer stream.cancel()
+		go srv.WatchSession(&WatchSessionRequest{}, stream)
+	}
+	waitForWatchers(srv, 2)
+
+	srv.ForceLogout("bob", "account disabled")
+
+	for _, stream := range streams {
+		event := stream.next(t)
+		if event.Type != SessionEvent_FORCED_LOGOUT || event.Message != "account disabled" {
+			t.Errorf("Expected a forced logout but got %v", event)
+		}
+		if !srv.revoked.has(stream.token) {
+			t.Error("Token of the session should be revoked")
+		}
+	}
+}
+
+func TestSessionBusKeepsTerminalEvents(t *testing.T) {
+	bus := newSessionBus()
+	watcher := bus.subscribe("bob", "token")
+	for i := 0; i < sessionEventBuffer+4; i++ {
+		bus.publishToken("token", &SessionEvent{Type: SessionEvent_EXPIRY_WARNING})
+	}
+	bus.publishToken("token", &SessionEvent{Type: SessionEvent_REVOKED})
+
+	var last *SessionEvent
+	for len(watcher.events) > 0 {
+		last = <-watcher.events
+	}
+	if last.GetType() != SessionEvent_REVOKED {
+		t.Errorf("Revocation should reach a lagging watcher but got %v", last)
+	}
+}
+
+func waitForWatchers(srv *LoginSrvServer, count int) {
+	for i := 0; i < 100; i++ {
+		srv.events.mu.Lock()
+		watching := len(srv.events.watchers)
+		srv.events.mu.Unlock()
+		if watching >= count {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type watchStreamStub struct {
+	grpc.ServerStream
+	ctx    context.Context
+	cancel context.CancelFunc
+	token  string
+	events chan *SessionEvent
+}
+
+func newWatchStreamStub(token string) *watchStreamStub {
+	ctx, cancel := context.WithCancel(md.NewIncomingContext(
+		context.Background(),
+		md.Pairs(AuthTokenMetadataKey, "bearer "+token),
+	))
+	return &watchStreamStub{ctx: ctx, cancel: cancel, token: token, events: make(chan *SessionEvent, 8)}
+}
+
+func (s *watchStreamStub) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStreamStub) Send(event *SessionEvent) error {
+	s.events <- event
+	return nil
+}
+
+func (s *watchStreamStub) next(t *testing.T) *SessionEvent {
+	select {
+	case event := <-s.events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("No session event received")
+		return nil
+	}
+}